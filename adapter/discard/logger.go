@@ -37,6 +37,10 @@ func (l *logger) Warn(_ ...interface{}) {}
 
 func (l *logger) Error(_ ...interface{}) {}
 
+func (l *logger) Logf(_ iface.Level, _ string, _ ...interface{}) {}
+
+func (l *logger) Log(_ iface.Level, _ ...interface{}) {}
+
 func (l *logger) WithFields(_ ...interface{}) iface.MessageLogger {
 	return l
 }
@@ -45,4 +49,4 @@ func (l *logger) Nested(_ ...interface{}) iface.Logger { return l }
 
 func (l *logger) SetOutput(_ io.Writer) {}
 
-func (l *logger) GetOutput() io.Writer { return nil }
+func (l *logger) GetOutput() io.Writer { return io.Discard }
@@ -0,0 +1,38 @@
+package discard
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	iface "github.com/anchore/go-logger"
+)
+
+func TestNew(t *testing.T) {
+	l := New()
+
+	c, ok := l.(iface.Controller)
+	assert.True(t, ok)
+	assert.Equal(t, io.Discard, c.GetOutput())
+
+	// setting an output has no effect
+	c.SetOutput(nil)
+	assert.Equal(t, io.Discard, c.GetOutput())
+
+	assert.Equal(t, l, l.Nested("key", "value"))
+	assert.Equal(t, l, l.WithFields("key", "value"))
+}
+
+func TestNew_DoesNotAllocate(t *testing.T) {
+	l := New()
+	err := errors.New("boom")
+
+	allocs := testing.AllocsPerRun(100, func() {
+		l.Info("message")
+		l.Errorf("failed: %v", err)
+		l.Nested("key", "value").WithFields("other", 1).Debug("message")
+	})
+	assert.Zero(t, allocs)
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+
+package eventlog
+
+// New creates a logger that writes entries to the Windows Event Log under the configured source. There is no Event
+// Log on other platforms, so all entries are discarded.
+func New(cfg Config) (Logger, error) {
+	return newLogger(nopReporter{}, cfg), nil
+}
+
+// nopReporter discards all events
+type nopReporter struct{}
+
+func (nopReporter) Info(uint32, string) error    { return nil }
+func (nopReporter) Warning(uint32, string) error { return nil }
+func (nopReporter) Error(uint32, string) error   { return nil }
+func (nopReporter) Close() error                 { return nil }
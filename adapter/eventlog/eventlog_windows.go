@@ -0,0 +1,18 @@
+//go:build windows
+
+package eventlog
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/windows/svc/eventlog"
+)
+
+// New creates a logger that writes entries to the Windows Event Log under the configured source.
+func New(cfg Config) (Logger, error) {
+	log, err := eventlog.Open(cfg.Source)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open event log for source %q: %w", cfg.Source, err)
+	}
+	return newLogger(log, cfg), nil
+}
@@ -0,0 +1,34 @@
+//go:build windows
+
+package eventlog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sys/windows/svc/eventlog"
+
+	iface "github.com/anchore/go-logger"
+)
+
+func TestNew(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode - it modifies system logs")
+	}
+
+	const source = "go-logger-test"
+	require.NoError(t, eventlog.InstallAsEventCreate(source, eventlog.Error|eventlog.Warning|eventlog.Info))
+	t.Cleanup(func() { assert.NoError(t, eventlog.Remove(source)) })
+
+	l, err := New(Config{Source: source, Level: iface.InfoLevel, EventID: 1})
+	require.NoError(t, err)
+
+	// the reporter is the event log itself, so errors are reported with the error event type
+	_, ok := l.(*logger).reporter.(*eventlog.Log)
+	require.True(t, ok)
+	assert.Equal(t, errorEvent, getEventType(iface.ErrorLevel))
+
+	l.WithFields("key", "value").Error("failed")
+	require.NoError(t, l.Close())
+}
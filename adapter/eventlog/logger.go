@@ -0,0 +1,211 @@
+package eventlog
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	iface "github.com/anchore/go-logger"
+)
+
+var _ Logger = (*logger)(nil)
+
+// Logger is an iface.Logger that writes entries to the Windows Event Log. Close must be called to release the handle
+// to the event log.
+type Logger interface {
+	iface.Logger
+	io.Closer
+}
+
+// Config contains all configurable values for the Event Log logger
+type Config struct {
+	// Source is the event source name entries are reported under, which should be registered (e.g. with
+	// eventlog.InstallAsEventCreate from golang.org/x/sys/windows/svc/eventlog) when the service is installed.
+	Source string
+	// Level is the most verbose level to log at.
+	Level iface.Level
+	// EventID is the event identifier all entries are reported with.
+	EventID uint32
+}
+
+// eventType is the Event Log type an entry is reported as
+type eventType int
+
+const (
+	infoEvent eventType = iota
+	warningEvent
+	errorEvent
+)
+
+// reporter writes events of each type to an event log (as implemented by *eventlog.Log on Windows)
+type reporter interface {
+	Info(eid uint32, msg string) error
+	Warning(eid uint32, msg string) error
+	Error(eid uint32, msg string) error
+	Close() error
+}
+
+type logger struct {
+	reporter reporter
+	// verbosity is the index of the configured level within iface.Levels (-1 when disabled)
+	verbosity int
+	eventID   uint32
+	fields    iface.Fields
+}
+
+func newLogger(r reporter, cfg Config) *logger {
+	return &logger{
+		reporter:  r,
+		verbosity: levelIndex(cfg.Level),
+		eventID:   cfg.EventID,
+	}
+}
+
+// Tracef takes a formatted template string and template arguments for the trace logging level.
+func (l *logger) Tracef(format string, args ...interface{}) {
+	l.report(iface.TraceLevel, fmt.Sprintf(format, args...))
+}
+
+// Debugf takes a formatted template string and template arguments for the debug logging level.
+func (l *logger) Debugf(format string, args ...interface{}) {
+	l.report(iface.DebugLevel, fmt.Sprintf(format, args...))
+}
+
+// Infof takes a formatted template string and template arguments for the info logging level.
+func (l *logger) Infof(format string, args ...interface{}) {
+	l.report(iface.InfoLevel, fmt.Sprintf(format, args...))
+}
+
+// Warnf takes a formatted template string and template arguments for the warning logging level.
+func (l *logger) Warnf(format string, args ...interface{}) {
+	l.report(iface.WarnLevel, fmt.Sprintf(format, args...))
+}
+
+// Errorf takes a formatted template string and template arguments for the error logging level.
+func (l *logger) Errorf(format string, args ...interface{}) {
+	l.report(iface.ErrorLevel, fmt.Sprintf(format, args...))
+}
+
+// Trace logs the given arguments at the trace logging level.
+func (l *logger) Trace(args ...interface{}) {
+	l.report(iface.TraceLevel, fmt.Sprint(args...))
+}
+
+// Debug logs the given arguments at the debug logging level.
+func (l *logger) Debug(args ...interface{}) {
+	l.report(iface.DebugLevel, fmt.Sprint(args...))
+}
+
+// Info logs the given arguments at the info logging level.
+func (l *logger) Info(args ...interface{}) {
+	l.report(iface.InfoLevel, fmt.Sprint(args...))
+}
+
+// Warn logs the given arguments at the warning logging level.
+func (l *logger) Warn(args ...interface{}) {
+	l.report(iface.WarnLevel, fmt.Sprint(args...))
+}
+
+// Error logs the given arguments at the error logging level.
+func (l *logger) Error(args ...interface{}) {
+	l.report(iface.ErrorLevel, fmt.Sprint(args...))
+}
+
+// Logf takes a formatted template string and template arguments for the given logging level.
+func (l *logger) Logf(level iface.Level, format string, args ...interface{}) {
+	iface.LogfAtLevel(l, level, format, args...)
+}
+
+// Log logs the given arguments at the given logging level.
+func (l *logger) Log(level iface.Level, args ...interface{}) {
+	iface.LogAtLevel(l, level, args...)
+}
+
+// WithFields returns a message logger with multiple key-value fields, which are appended to the message.
+func (l *logger) WithFields(fields ...interface{}) iface.MessageLogger {
+	return l.with(fields...)
+}
+
+// Nested returns a logger that appends the given key-value fields (along with any from this logger) to all messages.
+func (l *logger) Nested(fields ...interface{}) iface.Logger {
+	return l.with(fields...)
+}
+
+// Close releases the handle to the event log (which is shared by all loggers nested from this logger).
+func (l *logger) Close() error {
+	return l.reporter.Close()
+}
+
+func (l *logger) with(fields ...interface{}) *logger {
+	merged := make(iface.Fields, len(l.fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range iface.FieldsFrom(fields...) {
+		merged[k] = v
+	}
+	return &logger{
+		reporter:  l.reporter,
+		verbosity: l.verbosity,
+		eventID:   l.eventID,
+		fields:    merged,
+	}
+}
+
+func (l *logger) report(level iface.Level, msg string) {
+	if idx := levelIndex(level); idx < 0 || idx > l.verbosity {
+		return
+	}
+	msg += formatFields(l.fields)
+
+	// note: there is nowhere to report a failure to write to the event log
+	switch getEventType(level) {
+	case errorEvent:
+		_ = l.reporter.Error(l.eventID, msg)
+	case warningEvent:
+		_ = l.reporter.Warning(l.eventID, msg)
+	default:
+		_ = l.reporter.Info(l.eventID, msg)
+	}
+}
+
+// getEventType returns the Event Log type for the level. The Event Log has no types more verbose than information, so
+// debug and trace entries are reported as information.
+func getEventType(level iface.Level) eventType {
+	switch level {
+	case iface.ErrorLevel:
+		return errorEvent
+	case iface.WarnLevel:
+		return warningEvent
+	}
+	return infoEvent
+}
+
+// levelIndex returns the position of the level from least to most verbose, or -1 for DisabledLevel (and unknown levels)
+func levelIndex(level iface.Level) int {
+	for i, l := range iface.Levels() {
+		if l == level {
+			return i
+		}
+	}
+	return -1
+}
+
+// formatFields renders the fields as " key=value" pairs sorted by key
+func formatFields(fields iface.Fields) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&sb, " %s=%+v", k, fields[k])
+	}
+	return sb.String()
+}
@@ -0,0 +1,89 @@
+package eventlog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	iface "github.com/anchore/go-logger"
+)
+
+type reportedEvent struct {
+	eventType eventType
+	eventID   uint32
+	msg       string
+}
+
+// recordingReporter captures every event reported
+type recordingReporter struct {
+	events []reportedEvent
+	closed bool
+}
+
+func (r *recordingReporter) Info(eid uint32, msg string) error {
+	r.events = append(r.events, reportedEvent{eventType: infoEvent, eventID: eid, msg: msg})
+	return nil
+}
+
+func (r *recordingReporter) Warning(eid uint32, msg string) error {
+	r.events = append(r.events, reportedEvent{eventType: warningEvent, eventID: eid, msg: msg})
+	return nil
+}
+
+func (r *recordingReporter) Error(eid uint32, msg string) error {
+	r.events = append(r.events, reportedEvent{eventType: errorEvent, eventID: eid, msg: msg})
+	return nil
+}
+
+func (r *recordingReporter) Close() error {
+	r.closed = true
+	return nil
+}
+
+func Test_logger(t *testing.T) {
+	r := &recordingReporter{}
+	l := newLogger(r, Config{Level: iface.DebugLevel, EventID: 7})
+
+	l.Errorf("failed: %s", "boom")
+	l.Nested("pkg", "db").WithFields("table", "users", iface.Fields{"rows": 3}).Warn("slow query")
+	l.Info("started")
+	l.Log(iface.DebugLevel, "details")
+	l.Trace("too verbose")
+
+	require.NoError(t, l.Close())
+	assert.True(t, r.closed)
+
+	assert.Equal(t, []reportedEvent{
+		{eventType: errorEvent, eventID: 7, msg: "failed: boom"},
+		{eventType: warningEvent, eventID: 7, msg: "slow query pkg=db rows=3 table=users"},
+		{eventType: infoEvent, eventID: 7, msg: "started"},
+		{eventType: infoEvent, eventID: 7, msg: "details"},
+	}, r.events)
+}
+
+func Test_logger_Disabled(t *testing.T) {
+	r := &recordingReporter{}
+	l := newLogger(r, Config{Level: iface.DisabledLevel})
+
+	l.Error("failed")
+	assert.Empty(t, r.events)
+}
+
+func Test_getEventType(t *testing.T) {
+	tests := []struct {
+		level iface.Level
+		want  eventType
+	}{
+		{level: iface.ErrorLevel, want: errorEvent},
+		{level: iface.WarnLevel, want: warningEvent},
+		{level: iface.InfoLevel, want: infoEvent},
+		{level: iface.DebugLevel, want: infoEvent},
+		{level: iface.TraceLevel, want: infoEvent},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.level), func(t *testing.T) {
+			assert.Equal(t, tt.want, getEventType(tt.level))
+		})
+	}
+}
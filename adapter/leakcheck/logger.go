@@ -0,0 +1,160 @@
+package leakcheck
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	iface "github.com/anchore/go-logger"
+	"github.com/anchore/go-logger/adapter/redact"
+)
+
+var _ LeakDetector = (*leakDetector)(nil)
+var _ iface.Controller = (*leakDetector)(nil)
+
+// Violation is a log message that would have leaked a secret
+type Violation struct {
+	Level iface.Level
+	// Message is the message after redaction, so that violations never contain the secret themselves
+	Message string
+	// Fields are the keys of any fields attached to the message whose key or value contained a secret
+	Fields []string
+}
+
+// LeakDetector is a logger that produces no output, but records every message that contains a secret (according to
+// its redactor) as a violation. This is intended for tests to assert that no secret reaches the logger.
+type LeakDetector interface {
+	iface.Logger
+	// Violations returns all violations recorded so far (by this logger and all loggers nested from it), in the order
+	// they were logged.
+	Violations() []Violation
+}
+
+// violations are shared by a leak detector and all loggers nested from it
+type violations struct {
+	recorded []Violation
+	lock     sync.Mutex
+}
+
+type leakDetector struct {
+	redactor   redact.Redactor
+	violations *violations
+	fields     []interface{}
+}
+
+// New creates a logger that records a violation for every message (at any level) where the given redactor would
+// redact the message or any of its fields.
+func New(r redact.Redactor) LeakDetector {
+	return &leakDetector{
+		redactor:   r,
+		violations: &violations{},
+	}
+}
+
+func (l *leakDetector) Tracef(format string, args ...interface{}) {
+	l.check(iface.TraceLevel, fmt.Sprintf(format, args...))
+}
+
+func (l *leakDetector) Debugf(format string, args ...interface{}) {
+	l.check(iface.DebugLevel, fmt.Sprintf(format, args...))
+}
+
+func (l *leakDetector) Infof(format string, args ...interface{}) {
+	l.check(iface.InfoLevel, fmt.Sprintf(format, args...))
+}
+
+func (l *leakDetector) Warnf(format string, args ...interface{}) {
+	l.check(iface.WarnLevel, fmt.Sprintf(format, args...))
+}
+
+func (l *leakDetector) Errorf(format string, args ...interface{}) {
+	l.check(iface.ErrorLevel, fmt.Sprintf(format, args...))
+}
+
+func (l *leakDetector) Trace(args ...interface{}) {
+	l.check(iface.TraceLevel, fmt.Sprint(args...))
+}
+
+func (l *leakDetector) Debug(args ...interface{}) {
+	l.check(iface.DebugLevel, fmt.Sprint(args...))
+}
+
+func (l *leakDetector) Info(args ...interface{}) {
+	l.check(iface.InfoLevel, fmt.Sprint(args...))
+}
+
+func (l *leakDetector) Warn(args ...interface{}) {
+	l.check(iface.WarnLevel, fmt.Sprint(args...))
+}
+
+func (l *leakDetector) Error(args ...interface{}) {
+	l.check(iface.ErrorLevel, fmt.Sprint(args...))
+}
+
+func (l *leakDetector) Logf(level iface.Level, format string, args ...interface{}) {
+	iface.LogfAtLevel(l, level, format, args...)
+}
+
+func (l *leakDetector) Log(level iface.Level, args ...interface{}) {
+	iface.LogAtLevel(l, level, args...)
+}
+
+func (l *leakDetector) WithFields(fields ...interface{}) iface.MessageLogger {
+	return l.with(fields...)
+}
+
+func (l *leakDetector) Nested(fields ...interface{}) iface.Logger {
+	return l.with(fields...)
+}
+
+func (l *leakDetector) SetOutput(_ io.Writer) {}
+
+func (l *leakDetector) GetOutput() io.Writer {
+	return io.Discard
+}
+
+func (l *leakDetector) Violations() []Violation {
+	l.violations.lock.Lock()
+	defer l.violations.lock.Unlock()
+	result := make([]Violation, len(l.violations.recorded))
+	copy(result, l.violations.recorded)
+	return result
+}
+
+func (l *leakDetector) with(fields ...interface{}) *leakDetector {
+	return &leakDetector{
+		redactor:   l.redactor,
+		violations: l.violations,
+		fields:     append(append([]interface{}(nil), l.fields...), fields...),
+	}
+}
+
+func (l *leakDetector) check(level iface.Level, msg string) {
+	redacted := l.redactor.RedactString(msg)
+	leakedFields := l.leakedFields()
+	if redacted == msg && len(leakedFields) == 0 {
+		return
+	}
+
+	l.violations.lock.Lock()
+	defer l.violations.lock.Unlock()
+	l.violations.recorded = append(l.violations.recorded, Violation{
+		Level:   level,
+		Message: redacted,
+		Fields:  leakedFields,
+	})
+}
+
+// leakedFields returns the (sorted) keys of all attached fields where the key or value contains a secret
+func (l *leakDetector) leakedFields() []string {
+	var leaked []string
+	for k, v := range iface.FieldsFrom(l.fields...) {
+		value := fmt.Sprintf("%+v", v)
+		if l.redactor.RedactString(k) != k || l.redactor.RedactString(value) != value {
+			leaked = append(leaked, l.redactor.RedactString(k))
+		}
+	}
+	sort.Strings(leaked)
+	return leaked
+}
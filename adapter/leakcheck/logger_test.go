@@ -0,0 +1,47 @@
+package leakcheck
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	iface "github.com/anchore/go-logger"
+	"github.com/anchore/go-logger/adapter/redact"
+)
+
+func TestNew(t *testing.T) {
+	store := redact.NewStore("hunter2")
+	l := New(store)
+
+	l.Info("nothing to see here")
+	l.Debugf("the password is %s", "hunter2")
+	l.WithFields("password", "hunter2", "user", "alice").Warn("logging in")
+	nested := l.Nested("hunter2-key", "value")
+	nested.Error("failed")
+	nested.Nested("other", "value").Log(iface.TraceLevel, "still leaking")
+
+	assert.Equal(t, []Violation{
+		{Level: iface.DebugLevel, Message: "the password is *******"},
+		{Level: iface.WarnLevel, Message: "logging in", Fields: []string{"password"}},
+		{Level: iface.ErrorLevel, Message: "failed", Fields: []string{"*******-key"}},
+		{Level: iface.TraceLevel, Message: "still leaking", Fields: []string{"*******-key"}},
+	}, l.Violations())
+
+	// secrets added after the fact are detected too
+	store.Add("s3cr3t")
+	l.Info("s3cr3t")
+	assert.Len(t, l.Violations(), 5)
+
+	// no output is ever produced
+	assert.Equal(t, io.Discard, l.(iface.Controller).GetOutput())
+}
+
+func TestNew_NoViolations(t *testing.T) {
+	l := New(redact.NewStore("hunter2"))
+
+	l.Info("nothing to see here")
+	l.WithFields("user", "alice").Errorf("failed: %v", "timeout")
+
+	assert.Empty(t, l.Violations())
+}
@@ -0,0 +1,76 @@
+package logrus
+
+import (
+	"runtime"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// modulePath is the root of all packages that wrap logrus calls (this adapter, the core package, and other
+	// wrapping adapters such as redact)
+	modulePath = "github.com/anchore/go-logger"
+	logrusPath = "github.com/sirupsen/logrus"
+
+	// maxCallerDepth bounds how many frames are searched for the first frame outside of the logging packages
+	maxCallerDepth = 32
+)
+
+var _ logrus.Hook = (*callerHook)(nil)
+
+// callerHook corrects the caller reported by logrus. Logrus only skips its own frames when finding the caller, which
+// would report the method on this adapter (or another go-logger wrapper) instead of the call site of the logger.
+type callerHook struct{}
+
+func (callerHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (callerHook) Fire(entry *logrus.Entry) error {
+	if entry.Caller == nil {
+		return nil
+	}
+	if frame, ok := findCaller(); ok {
+		entry.Caller = &frame
+	}
+	return nil
+}
+
+// findCaller returns the first frame on the stack that is not within logrus or go-logger
+func findCaller() (runtime.Frame, bool) {
+	pcs := make([]uintptr, maxCallerDepth)
+	// skip runtime.Callers and findCaller
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !isLoggingFrame(frame) {
+			return frame, true
+		}
+		if !more {
+			return runtime.Frame{}, false
+		}
+	}
+}
+
+func isLoggingFrame(frame runtime.Frame) bool {
+	// tests within go-logger are callers of the logger, not part of it
+	if strings.HasSuffix(frame.File, "_test.go") {
+		return false
+	}
+	pkg := packageName(frame.Function)
+	return pkg == logrusPath || pkg == modulePath || strings.HasPrefix(pkg, modulePath+"/")
+}
+
+// packageName returns the package path of a fully qualified function name (e.g. "github.com/org/pkg.(*T).Method")
+func packageName(function string) string {
+	lastSlash := strings.LastIndex(function, "/")
+	if lastSlash < 0 {
+		lastSlash = 0
+	}
+	if dot := strings.Index(function[lastSlash:], "."); dot >= 0 {
+		return function[:lastSlash+dot]
+	}
+	return function
+}
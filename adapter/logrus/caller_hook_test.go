@@ -0,0 +1,26 @@
+package logrus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_packageName(t *testing.T) {
+	tests := []struct {
+		function string
+		want     string
+	}{
+		{function: "github.com/anchore/go-logger/adapter/logrus.(*logger).Infof", want: "github.com/anchore/go-logger/adapter/logrus"},
+		{function: "github.com/anchore/go-logger.LogAtLevel", want: "github.com/anchore/go-logger"},
+		{function: "github.com/sirupsen/logrus.(*Entry).Log", want: "github.com/sirupsen/logrus"},
+		// dots in the last path element are escaped in symbol names
+		{function: "gopkg.in/yaml%2ev3.Marshal", want: "gopkg.in/yaml%2ev3"},
+		{function: "main.main.func1", want: "main"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			assert.Equal(t, tt.want, packageName(tt.function))
+		})
+	}
+}
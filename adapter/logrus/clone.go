@@ -0,0 +1,73 @@
+package logrus
+
+import (
+	"io"
+
+	"github.com/sirupsen/logrus"
+
+	iface "github.com/anchore/go-logger"
+)
+
+var _ iface.Cloner = (*logger)(nil)
+var _ iface.Cloner = (*nestedLogger)(nil)
+var _ iface.Controller = (*clonedLogger)(nil)
+
+// Clone returns a copy of the logger with the same level, formatter and hooks (including any level files), writing to
+// the same output until SetOutput is called on the clone. Closing the clone does not close files opened by the parent.
+func (l *logger) Clone() iface.Logger {
+	return &logger{
+		config:  l.config,
+		logger:  cloneLogrus(l.logger, l.config.NoLock),
+		output:  l.output,
+		outputs: append([]string(nil), l.outputs...),
+	}
+}
+
+// clonedLogger is a nested logger backed by its own Logrus logger, so that its output can be changed independently of
+// the logger it was cloned from
+type clonedLogger struct {
+	nestedLogger
+}
+
+// Clone returns a copy of the nested logger with the same level, formatter, hooks and fields, writing to the same
+// output until SetOutput is called on the clone.
+func (l *nestedLogger) Clone() iface.Logger {
+	// the lock setting of the original logger cannot be determined, so the clone always locks
+	cloned := cloneLogrus(l.entry.Logger, false)
+	return &clonedLogger{
+		nestedLogger: nestedLogger{
+			entry:      cloned.WithFields(l.entry.Data),
+			extractors: l.extractors,
+		},
+	}
+}
+
+func (l *clonedLogger) SetOutput(writer io.Writer) {
+	l.entry.Logger.SetOutput(writer)
+}
+
+func (l *clonedLogger) GetOutput() io.Writer {
+	return l.entry.Logger.Out
+}
+
+// cloneLogrus returns a new Logrus logger with the configuration of the given logger. Hooks are shared, but the set of
+// hooks is copied so that adding hooks to either logger does not affect the other.
+func cloneLogrus(l *logrus.Logger, noLock bool) *logrus.Logger {
+	hooks := make(logrus.LevelHooks, len(l.Hooks))
+	for level, levelHooks := range l.Hooks {
+		hooks[level] = append([]logrus.Hook(nil), levelHooks...)
+	}
+	cloned := &logrus.Logger{
+		Out:          l.Out,
+		Hooks:        hooks,
+		Formatter:    l.Formatter,
+		ReportCaller: l.ReportCaller,
+		Level:        l.GetLevel(),
+		ExitFunc:     l.ExitFunc,
+		BufferPool:   l.BufferPool,
+	}
+	if noLock {
+		cloned.SetNoLock()
+	}
+	return cloned
+}
@@ -171,11 +171,54 @@ func (f *TextFormatter) checkIfTerminal(w io.Writer) bool {
 	}
 }
 
+// withoutColors returns a copy of the formatter with colors disabled, leaving the formatter itself untouched (it may
+// be shared with other loggers)
+func (f *TextFormatter) withoutColors() *TextFormatter {
+	return &TextFormatter{
+		ForceColors:      f.ForceColors,
+		DisableColors:    true,
+		ForceFormatting:  f.ForceFormatting,
+		DisableTimestamp: f.DisableTimestamp,
+		DisableUppercase: f.DisableUppercase,
+		FullTimestamp:    f.FullTimestamp,
+		TimestampFormat:  f.TimestampFormat,
+		DisableSorting:   f.DisableSorting,
+		QuoteEmptyFields: f.QuoteEmptyFields,
+		QuoteCharacter:   f.QuoteCharacter,
+		SpacePadding:     f.SpacePadding,
+		colorScheme:      f.colorScheme,
+	}
+}
+
 func (f *TextFormatter) SetColorScheme(colorScheme *ColorScheme) {
 	f.colorScheme = compileColorScheme(colorScheme)
 }
 
+// uncoloredFormatter is implemented by formatters that can format entries without colors regardless of how they are
+// configured (e.g. for writing to files)
+type uncoloredFormatter interface {
+	formatUncolored(entry *logrus.Entry) ([]byte, error)
+}
+
+var _ uncoloredFormatter = (*TextFormatter)(nil)
+
+// formatUncolored formats the entry without colors when the formatter supports it, otherwise formatting as usual
+func formatUncolored(formatter logrus.Formatter, entry *logrus.Entry) ([]byte, error) {
+	if u, ok := formatter.(uncoloredFormatter); ok {
+		return u.formatUncolored(entry)
+	}
+	return formatter.Format(entry)
+}
+
 func (f *TextFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	return f.format(entry, true)
+}
+
+func (f *TextFormatter) formatUncolored(entry *logrus.Entry) ([]byte, error) {
+	return f.format(entry, false)
+}
+
+func (f *TextFormatter) format(entry *logrus.Entry, allowColors bool) ([]byte, error) {
 	var b *bytes.Buffer
 	var keys = make([]string, 0, len(entry.Data))
 	for k := range entry.Data {
@@ -203,7 +246,7 @@ func (f *TextFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 		timestampFormat = defaultTimestampFormat
 	}
 	if isFormatted {
-		isColored := (f.ForceColors || f.isTerminal) && !f.DisableColors
+		isColored := allowColors && (f.ForceColors || f.isTerminal) && !f.DisableColors
 		var colorScheme *compiledColorScheme
 		if isColored {
 			if f.colorScheme == nil {
@@ -320,11 +363,12 @@ func (f *TextFormatter) needsQuoting(text string) bool {
 	return false
 }
 
+// prefixPattern matches a "[prefix]" at the start of a message (compiled once, as this is checked for every entry)
+var prefixPattern = regexp.MustCompile(`^\[(.*?)]`)
+
 func extractPrefix(msg string) (string, string) {
 	prefix := ""
-	regex := regexp.MustCompile(`^\[(.*?)]`)
-	if regex.MatchString(msg) {
-		match := regex.FindString(msg)
+	if match := prefixPattern.FindString(msg); match != "" {
 		prefix, msg = match[1:len(match)-1], strings.TrimSpace(msg[len(match):])
 	}
 	return prefix, msg
@@ -1,9 +1,14 @@
 package logrus
 
 import (
+	"io"
 	"testing"
 
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	iface "github.com/anchore/go-logger"
 )
 
 func Test_extractPrefix(t *testing.T) {
@@ -35,3 +40,50 @@ func Test_extractPrefix(t *testing.T) {
 		})
 	}
 }
+
+func Test_extractPrefix_DoesNotAllocate(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocations cannot be measured with the race detector enabled")
+	}
+	allocs := testing.AllocsPerRun(100, func() {
+		extractPrefix("[0000] hello world")
+	})
+	assert.Zero(t, allocs)
+}
+
+func Benchmark_Formatters(b *testing.B) {
+	formatters := []struct {
+		name      string
+		formatter logrus.Formatter
+	}{
+		{name: "text", formatter: DefaultTextFormatter()},
+		{name: "json", formatter: DefaultJSONFormatter()},
+	}
+	for _, f := range formatters {
+		b.Run(f.name+"/no fields", func(b *testing.B) {
+			l := newBenchmarkLogger(b, f.formatter)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				l.Info("the quick brown fox jumps over the lazy dog")
+			}
+			b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "msgs/s")
+		})
+		b.Run(f.name+"/with fields", func(b *testing.B) {
+			l := newBenchmarkLogger(b, f.formatter).WithFields("user", "bob", "attempt", 3, "elapsed", 1.5)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				l.Info("the quick brown fox jumps over the lazy dog")
+			}
+			b.ReportMetric(float64(b.N)/b.Elapsed().Seconds(), "msgs/s")
+		})
+	}
+}
+
+func newBenchmarkLogger(b *testing.B, formatter logrus.Formatter) iface.Logger {
+	l, err := New(Config{Level: iface.InfoLevel, Formatter: formatter})
+	require.NoError(b, err)
+	l.(iface.Controller).SetOutput(io.Discard)
+	return l
+}
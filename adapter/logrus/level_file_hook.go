@@ -0,0 +1,77 @@
+package logrus
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	iface "github.com/anchore/go-logger"
+)
+
+var _ logrus.Hook = (*levelFileHook)(nil)
+
+// levelFileHook routes each entry to the file configured for the entry's level (e.g. errors to error.log)
+type levelFileHook struct {
+	formatter logrus.Formatter
+	writers   map[logrus.Level]io.Writer
+	files     []io.Closer
+	lock      sync.Mutex
+}
+
+func newLevelFileHook(cfg Config, formatter logrus.Formatter) (*levelFileHook, error) {
+	writers := make(map[logrus.Level]io.Writer)
+	opened := make(map[string]io.Writer)
+	var files []io.Closer
+	for level, location := range cfg.LevelFileLocations {
+		if level == iface.DisabledLevel || location == "" {
+			continue
+		}
+		w, ok := opened[location]
+		if !ok {
+			logFile, err := openLogFile(location, cfg)
+			if err != nil {
+				_ = closeAll(files)
+				return nil, fmt.Errorf("unable to setup %s log file: %w", level, err)
+			}
+			files = append(files, logFile)
+			w = logFile
+			opened[location] = w
+		}
+		writers[getLogLevel(level)] = w
+	}
+	return &levelFileHook{
+		formatter: formatter,
+		writers:   writers,
+		files:     files,
+	}, nil
+}
+
+func (h *levelFileHook) Levels() []logrus.Level {
+	levels := make([]logrus.Level, 0, len(h.writers))
+	for level := range h.writers {
+		levels = append(levels, level)
+	}
+	return levels
+}
+
+func (h *levelFileHook) Fire(entry *logrus.Entry) error {
+	w, ok := h.writers[entry.Level]
+	if !ok {
+		return nil
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	// level files are never written with colors, even when the primary output is a terminal
+	serialized, err := formatUncolored(h.formatter, entry)
+	if err != nil {
+		return fmt.Errorf("unable to format entry for level file: %w", err)
+	}
+	if _, err := w.Write(serialized); err != nil {
+		return fmt.Errorf("unable to write entry to level file: %w", err)
+	}
+	return nil
+}
@@ -1,6 +1,8 @@
 package logrus
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -10,24 +12,73 @@ import (
 	"github.com/sirupsen/logrus"
 
 	iface "github.com/anchore/go-logger"
+	"github.com/anchore/go-logger/internal/logfile"
 )
 
 var _ iface.Logger = (*logger)(nil)
 var _ iface.Controller = (*logger)(nil)
+var _ iface.ContextLogger = (*logger)(nil)
+var _ iface.LevelEnabler = (*logger)(nil)
 
 const (
-	defaultLogFilePermissions fs.FileMode = 0644
-	timestampFormat                       = "2006-01-02 15:04:05"
+	timestampFormat = "2006-01-02 15:04:05"
 )
 
 // Config contains all configurable values for the Logrus entry
 type Config struct {
-	EnableConsole     bool
-	FileLocation      string
-	Level             iface.Level
-	Formatter         logrus.Formatter
+	EnableConsole bool
+	FileLocation  string
+	// Level is the most verbose level to log at. Any spelling accepted by iface.LevelFromString may be used (e.g.
+	// "INFO" or "warning"). An unset level defaults to info, and an unrecognized level is an error.
+	Level     iface.Level
+	Formatter logrus.Formatter
+	// CaptureCallerInfo reports the function and file:line that emitted each entry (included by the JSON formatter as
+	// the "func" and "file" fields). The reported location is the caller of the logger, not this adapter.
 	CaptureCallerInfo bool
 	NoLock            bool
+	// NoColor disables colors in the text formatter (using a copy of the configured *TextFormatter with DisableColors
+	// set, so the configured formatter is left unchanged). Otherwise colors are only used when the output is a terminal
+	// (or the formatter has ForceColors set). Note that when both EnableConsole and FileLocation are set, the same
+	// formatted bytes are written to both, which are not colored since the combined output is not a terminal. Entries
+	// written to LevelFileLocations are never colored.
+	NoColor bool
+	// LevelFileLocations additionally writes entries of each given level to the mapped file (e.g. ErrorLevel to
+	// "error.log"). Entries are still written to the primary output as configured by EnableConsole and FileLocation.
+	LevelFileLocations map[iface.Level]string
+	// IncludeUptime attaches an "uptime" field to every entry with the elapsed time since the logger was created.
+	IncludeUptime bool
+	// TruncateFiles discards any existing content of log files when opened, otherwise entries are appended.
+	TruncateFiles bool
+	// FilePermissions is used when creating log files (defaults to 0644).
+	FilePermissions fs.FileMode
+	// MaxSizeMB is the size in megabytes at which log files are rotated. Rotation is enabled when any of MaxSizeMB,
+	// MaxBackups, MaxAgeDays or Compress are set, in which case MaxSizeMB defaults to 100.
+	MaxSizeMB int
+	// MaxBackups is the number of rotated log files to keep (all are kept by default, subject to MaxAgeDays).
+	MaxBackups int
+	// MaxAgeDays is the number of days to keep rotated log files (they are kept regardless of age by default).
+	MaxAgeDays int
+	// Compress gzips rotated log files.
+	Compress bool
+	// ErrorStackDepth attaches a "stack" field with up to this many frames of the call site's stack trace (innermost
+	// first) to entries at error level. Stacks are not captured when not positive (the default).
+	ErrorStackDepth int
+	// MaxFields caps the number of fields attached to each entry (unlimited when not positive). Entries with more fields
+	// keep the first MaxFields fields sorted by key, and are marked with a "fields_truncated" field. Fields added by this
+	// adapter (e.g. "uptime") are not counted.
+	MaxFields int
+	// SensitiveFieldKeys are field keys (matched case-insensitively) whose values are always masked in the output,
+	// regardless of content (e.g. "password" or "authorization").
+	SensitiveFieldKeys []string
+	// Hooks are added to the underlying logrus logger (e.g. to count or alert on entries). Hooks only fire for entries
+	// at or above the configured Level, and see the entry before it is written to the output. This means that a
+	// redacting output writer has not yet redacted the message seen by hooks; wrap the logger with redact.New to redact
+	// messages before they reach any hooks.
+	Hooks []logrus.Hook
+	// ContextExtractors are used by WithContext to find the fields (e.g. a request ID) to attach from a context.
+	ContextExtractors []iface.ContextExtractor
+	// Middleware is applied in order to every entry before it is formatted, and may modify or drop entries.
+	Middleware []iface.Middleware
 }
 
 func DefaultConfig() Config {
@@ -41,6 +92,14 @@ func DefaultConfig() Config {
 	}
 }
 
+// DefaultConfigForVerbosity returns the default configuration at the level for the given verbosity (see
+// iface.DefaultLevelForVerbosity).
+func DefaultConfigForVerbosity(v int) Config {
+	cfg := DefaultConfig()
+	cfg.Level = iface.DefaultLevelForVerbosity(v)
+	return cfg
+}
+
 func DefaultTextFormatter() logrus.Formatter {
 	return &TextFormatter{
 		TimestampFormat: timestampFormat,
@@ -59,42 +118,52 @@ func DefaultJSONFormatter() logrus.Formatter {
 
 // logger contains all runtime values for using Logrus with the configured output target and input configuration values.
 type logger struct {
-	config Config
-	logger *logrus.Logger
-	output io.Writer
+	config  Config
+	logger  *logrus.Logger
+	output  io.Writer
+	outputs []string
+	// files are all log files opened by this logger, which are closed by Close
+	files []io.Closer
 }
 
 // Use adapts the given logger based on the provided configuration
 func Use(l *logrus.Logger, cfg Config) (iface.Logger, error) {
+	level, err := normalizeLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Level = level
+
 	var output io.Writer
+	var outputs []string
+	var files []io.Closer
 	switch {
 	case cfg.EnableConsole && cfg.FileLocation != "":
-		logFile, err := os.OpenFile(cfg.FileLocation, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, defaultLogFilePermissions)
+		logFile, err := openLogFile(cfg.FileLocation, cfg)
 		if err != nil {
 			return nil, fmt.Errorf("unable to setup log file: %w", err)
 		}
 		output = io.MultiWriter(os.Stderr, logFile)
+		outputs = append(describeOutput(os.Stderr), describeOutput(logFile)...)
+		files = append(files, logFile)
 	case cfg.EnableConsole:
 		output = os.Stderr
+		outputs = describeOutput(output)
 	case cfg.FileLocation != "":
-		logFile, err := os.OpenFile(cfg.FileLocation, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, defaultLogFilePermissions)
+		logFile, err := openLogFile(cfg.FileLocation, cfg)
 		if err != nil {
 			return nil, fmt.Errorf("unable to setup log file: %w", err)
 		}
 		output = logFile
+		outputs = describeOutput(output)
+		files = append(files, logFile)
 	default:
 		output = ioutil.Discard
-	}
-
-	var level logrus.Level
-	if cfg.Level == iface.DisabledLevel {
-		level = logrus.PanicLevel
-	} else {
-		level = getLogLevel(cfg.Level)
+		outputs = describeOutput(output)
 	}
 
 	l.SetOutput(output)
-	l.SetLevel(level)
+	l.SetLevel(getLogLevel(cfg.Level))
 	l.SetReportCaller(cfg.CaptureCallerInfo)
 
 	if cfg.NoLock {
@@ -107,10 +176,57 @@ func Use(l *logrus.Logger, cfg Config) (iface.Logger, error) {
 		l.SetFormatter(DefaultTextFormatter())
 	}
 
+	if tf, ok := l.Formatter.(*TextFormatter); ok && cfg.NoColor {
+		l.SetFormatter(tf.withoutColors())
+	}
+
+	if len(cfg.Middleware) > 0 {
+		l.SetFormatter(newMiddlewareFormatter(l.Formatter, cfg.Middleware))
+	}
+
+	if cfg.CaptureCallerInfo {
+		// this must be the first hook, so that all other hooks see the corrected caller
+		l.AddHook(callerHook{})
+	}
+
+	if len(cfg.SensitiveFieldKeys) > 0 {
+		l.AddHook(newSensitiveFieldsHook(cfg.SensitiveFieldKeys))
+	}
+
+	if cfg.MaxFields > 0 {
+		l.AddHook(maxFieldsHook{max: cfg.MaxFields})
+	}
+
+	if cfg.IncludeUptime {
+		l.AddHook(newUptimeHook())
+	}
+
+	if cfg.ErrorStackDepth > 0 {
+		l.AddHook(stackHook{depth: cfg.ErrorStackDepth})
+	}
+
+	for _, hook := range cfg.Hooks {
+		if hook != nil {
+			l.AddHook(hook)
+		}
+	}
+
+	if len(cfg.LevelFileLocations) > 0 {
+		hook, err := newLevelFileHook(cfg, l.Formatter)
+		if err != nil {
+			_ = closeAll(files)
+			return nil, err
+		}
+		l.AddHook(hook)
+		files = append(files, hook.files...)
+	}
+
 	return &logger{
-		config: cfg,
-		logger: l,
-		output: output,
+		config:  cfg,
+		logger:  l,
+		output:  output,
+		outputs: outputs,
+		files:   files,
 	}, nil
 }
 
@@ -169,17 +285,38 @@ func (l *logger) Error(args ...interface{}) {
 	l.logger.Error(args...)
 }
 
+// Logf takes a formatted template string and template arguments for the given logging level.
+func (l *logger) Logf(level iface.Level, format string, args ...interface{}) {
+	iface.LogfAtLevel(l, level, format, args...)
+}
+
+// Log logs the given arguments at the given logging level.
+func (l *logger) Log(level iface.Level, args ...interface{}) {
+	iface.LogAtLevel(l, level, args...)
+}
+
 // WithFields returns a message entry with multiple key-value fields.
 func (l *logger) WithFields(fields ...interface{}) iface.MessageLogger {
-	return l.logger.WithFields(getFields(fields...))
+	return &nestedLogger{entry: l.logger.WithFields(logrus.Fields(iface.FieldsFrom(fields...))), extractors: l.config.ContextExtractors}
 }
 
 func (l *logger) Nested(fields ...interface{}) iface.Logger {
-	return &nestedLogger{entry: l.logger.WithFields(getFields(fields...))}
+	return &nestedLogger{entry: l.logger.WithFields(logrus.Fields(iface.FieldsFrom(fields...))), extractors: l.config.ContextExtractors}
+}
+
+// WithContext returns a logger nested with all fields found in the context by the configured ContextExtractors.
+func (l *logger) WithContext(ctx context.Context) iface.Logger {
+	return l.Nested(iface.ContextFields(ctx, l.config.ContextExtractors...)...)
+}
+
+// IsLevelEnabled reports whether messages at the given level are logged.
+func (l *logger) IsLevelEnabled(level iface.Level) bool {
+	return isLevelEnabled(l.logger, level)
 }
 
 func (l *logger) SetOutput(writer io.Writer) {
 	l.output = writer
+	l.outputs = describeOutput(writer)
 	l.logger.SetOutput(writer)
 }
 
@@ -187,25 +324,79 @@ func (l *logger) GetOutput() io.Writer {
 	return l.output
 }
 
-func getFields(fields ...interface{}) logrus.Fields {
-	f := make(logrus.Fields)
-	offset := 0
-	for i, val := range fields {
-		// there can be a fields map anywhere within the parameters
-		if fieldsMap, ok := val.(iface.Fields); ok {
-			for k, v := range fieldsMap {
-				f[k] = v
-			}
-			offset++
-			continue
+// Outputs returns human-readable descriptions of where log entries are currently being written (e.g. "stderr" or
+// "file:/var/log/app.log").
+func (l *logger) Outputs() []string {
+	return append([]string(nil), l.outputs...)
+}
+
+// OutputDescriber may be implemented by io.Writers that wrap other writers, allowing Outputs() to describe the full
+// output chain.
+type OutputDescriber interface {
+	DescribeOutput() []string
+}
+
+func describeOutput(w io.Writer) []string {
+	switch v := w.(type) {
+	case nil:
+		return nil
+	case OutputDescriber:
+		return v.DescribeOutput()
+	case *os.File:
+		switch v {
+		case os.Stderr:
+			return []string{"stderr"}
+		case os.Stdout:
+			return []string{"stdout"}
 		}
+		return []string{"file:" + v.Name()}
+	}
+	if w == ioutil.Discard {
+		return []string{"discard"}
+	}
+	return []string{fmt.Sprintf("%T", w)}
+}
+
+// Close closes all log files opened by the logger (including any level files), returning any errors encountered.
+// The logger should not be used after Close.
+func (l *logger) Close() error {
+	return closeAll(l.files)
+}
 
-		// virtually skip any field maps found when figuring if this is a key or a value
-		if (i-offset)%2 != 0 {
-			f[fmt.Sprintf("%s", fields[i-1])] = val
+func closeAll(closers []io.Closer) error {
+	var errs []error
+	for _, c := range closers {
+		if err := c.Close(); err != nil {
+			errs = append(errs, err)
 		}
 	}
-	return f
+	return errors.Join(errs...)
+}
+
+// openLogFile opens the given log file for writing, which is rotated when any rotation settings are configured
+func openLogFile(location string, cfg Config) (io.WriteCloser, error) {
+	if cfg.rotationEnabled() {
+		return openRotatingFile(location, cfg)
+	}
+	return openFile(location, cfg)
+}
+
+func openFile(location string, cfg Config) (*os.File, error) {
+	return logfile.Open(location, cfg.TruncateFiles, cfg.FilePermissions)
+}
+
+// normalizeLevel maps any spelling of a level to its canonical value, defaulting an unset level to info rather than
+// silently disabling logging
+func normalizeLevel(level iface.Level) (iface.Level, error) {
+	if level == iface.DisabledLevel {
+		return iface.InfoLevel, nil
+	}
+	return iface.LevelFromString(string(level))
+}
+
+// isLevelEnabled reports whether the logger logs messages at the given level (unknown levels are never logged)
+func isLevelEnabled(l *logrus.Logger, level iface.Level) bool {
+	return iface.IsLevel(level, iface.Levels()...) && l.IsLevelEnabled(getLogLevel(level))
 }
 
 func getLogLevel(level iface.Level) logrus.Level {
@@ -0,0 +1,753 @@
+package logrus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	iface "github.com/anchore/go-logger"
+	"github.com/anchore/go-logger/internal/logfile"
+)
+
+func Test_logger_Outputs(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "app.log")
+
+	l, err := New(Config{
+		EnableConsole: true,
+		FileLocation:  logFile,
+		Level:         iface.InfoLevel,
+	})
+	require.NoError(t, err)
+
+	o, ok := l.(interface{ Outputs() []string })
+	require.True(t, ok)
+	assert.Equal(t, []string{"stderr", "file:" + logFile}, o.Outputs())
+
+	l.(iface.Controller).SetOutput(&bytes.Buffer{})
+	assert.Equal(t, []string{"*bytes.Buffer"}, o.Outputs())
+}
+
+func Test_logger_LevelFileLocations(t *testing.T) {
+	dir := t.TempDir()
+	errorLog := filepath.Join(dir, "error.log")
+	infoLog := filepath.Join(dir, "info.log")
+
+	l, err := New(Config{
+		Level: iface.InfoLevel,
+		LevelFileLocations: map[iface.Level]string{
+			iface.ErrorLevel: errorLog,
+			iface.InfoLevel:  infoLog,
+		},
+	})
+	require.NoError(t, err)
+
+	aggregate := &bytes.Buffer{}
+	l.(iface.Controller).SetOutput(aggregate)
+
+	l.Error("the error line")
+	l.Info("the info line")
+	l.Debug("the debug line")
+
+	errorContents, err := os.ReadFile(errorLog)
+	require.NoError(t, err)
+	infoContents, err := os.ReadFile(infoLog)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(errorContents), "the error line")
+	assert.NotContains(t, string(errorContents), "the info line")
+
+	assert.Contains(t, string(infoContents), "the info line")
+	assert.NotContains(t, string(infoContents), "the error line")
+
+	for _, contents := range []string{string(errorContents), string(infoContents), aggregate.String()} {
+		assert.NotContains(t, contents, "the debug line")
+	}
+	assert.Contains(t, aggregate.String(), "the error line")
+	assert.Contains(t, aggregate.String(), "the info line")
+}
+
+func Test_logger_IncludeUptime(t *testing.T) {
+	current := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	original := now
+	now = func() time.Time { return current }
+	t.Cleanup(func() { now = original })
+
+	l, err := New(Config{
+		Level:         iface.InfoLevel,
+		IncludeUptime: true,
+		Formatter:     DefaultJSONFormatter(),
+	})
+	require.NoError(t, err)
+
+	buff := &bytes.Buffer{}
+	l.(iface.Controller).SetOutput(buff)
+
+	current = current.Add(2 * time.Second)
+	l.Info("first")
+	current = current.Add(3 * time.Second)
+	l.Nested("pkg", "a").Info("second")
+
+	lines := strings.Split(strings.TrimSpace(buff.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var uptimes []time.Duration
+	for _, line := range lines {
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		uptimes = append(uptimes, time.Duration(entry["uptime"].(float64)))
+	}
+	assert.Equal(t, []time.Duration{2 * time.Second, 5 * time.Second}, uptimes)
+}
+
+func Test_logger_UptimeExcludedByDefault(t *testing.T) {
+	l, err := New(Config{Level: iface.InfoLevel, Formatter: DefaultJSONFormatter()})
+	require.NoError(t, err)
+
+	buff := &bytes.Buffer{}
+	l.(iface.Controller).SetOutput(buff)
+	l.Info("hello")
+
+	assert.NotContains(t, buff.String(), "uptime")
+}
+
+func Test_logger_FileAppendAndTruncate(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "app.log")
+	require.NoError(t, os.WriteFile(logFile, []byte("previous run\n"), 0600))
+
+	l, err := New(Config{FileLocation: logFile, Level: iface.InfoLevel})
+	require.NoError(t, err)
+	l.Info("appended line")
+
+	contents, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(contents), "previous run\n"))
+	assert.Contains(t, string(contents), "appended line")
+
+	l, err = New(Config{FileLocation: logFile, Level: iface.InfoLevel, TruncateFiles: true})
+	require.NoError(t, err)
+	l.Info("only line")
+
+	contents, err = os.ReadFile(logFile)
+	require.NoError(t, err)
+	assert.NotContains(t, string(contents), "previous run")
+	assert.NotContains(t, string(contents), "appended line")
+	assert.Contains(t, string(contents), "only line")
+}
+
+func Test_logger_FilePermissions(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name   string
+		perm   os.FileMode
+		expect os.FileMode
+	}{
+		{name: "default", perm: 0, expect: logfile.DefaultPermissions},
+		{name: "configured", perm: 0600, expect: 0600},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logFile := filepath.Join(dir, tt.name+".log")
+			errorLog := filepath.Join(dir, tt.name+"-error.log")
+			_, err := New(Config{
+				FileLocation:       logFile,
+				Level:              iface.InfoLevel,
+				FilePermissions:    tt.perm,
+				LevelFileLocations: map[iface.Level]string{iface.ErrorLevel: errorLog},
+			})
+			require.NoError(t, err)
+
+			for _, f := range []string{logFile, errorLog} {
+				info, err := os.Stat(f)
+				require.NoError(t, err)
+				// the process umask may only remove permissions
+				assert.Zero(t, info.Mode().Perm()&^tt.expect, "unexpected permissions %v for %s", info.Mode().Perm(), f)
+			}
+		})
+	}
+}
+
+func Test_logger_Trace(t *testing.T) {
+	tests := []struct {
+		name  string
+		level iface.Level
+		want  bool
+	}{
+		{name: "emitted at trace level", level: iface.TraceLevel, want: true},
+		{name: "suppressed at debug level", level: iface.DebugLevel, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := New(Config{Level: tt.level})
+			require.NoError(t, err)
+
+			buff := &bytes.Buffer{}
+			l.(iface.Controller).SetOutput(buff)
+
+			l.Trace("trace line")
+			l.Tracef("formatted %s line", "trace")
+			l.Nested("key", "value").Trace("nested trace line")
+
+			for _, msg := range []string{"trace line", "formatted trace line", "nested trace line"} {
+				if tt.want {
+					assert.Contains(t, buff.String(), msg)
+				} else {
+					assert.NotContains(t, buff.String(), msg)
+				}
+			}
+		})
+	}
+}
+
+func Test_logger_LevelNormalization(t *testing.T) {
+	tests := []struct {
+		name      string
+		level     iface.Level
+		wantInfo  bool
+		wantDebug bool
+	}{
+		{name: "canonical level", level: iface.InfoLevel, wantInfo: true},
+		{name: "uppercase level", level: "INFO", wantInfo: true},
+		{name: "alias", level: "debugging", wantInfo: true, wantDebug: true},
+		{name: "zero value defaults to info", level: iface.DisabledLevel, wantInfo: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := New(Config{Level: tt.level})
+			require.NoError(t, err)
+
+			buff := &bytes.Buffer{}
+			l.(iface.Controller).SetOutput(buff)
+
+			l.Info("info line")
+			l.Debug("debug line")
+
+			assert.Equal(t, tt.wantInfo, strings.Contains(buff.String(), "info line"))
+			assert.Equal(t, tt.wantDebug, strings.Contains(buff.String(), "debug line"))
+		})
+	}
+}
+
+func Test_logger_UnknownLevel(t *testing.T) {
+	_, err := New(Config{Level: "verbose-ish"})
+
+	var levelErr *iface.UnknownLevelError
+	require.ErrorAs(t, err, &levelErr)
+	assert.Equal(t, "verbose-ish", levelErr.Value)
+}
+
+func Test_logger_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "app.log")
+
+	l, err := New(Config{
+		EnableConsole: true,
+		FileLocation:  logFile,
+		Level:         iface.InfoLevel,
+		Formatter:     &logrus.JSONFormatter{},
+		MaxSizeMB:     1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"stderr", "rotating-file:" + logFile}, l.(interface{ Outputs() []string }).Outputs())
+
+	// write to the file directly (rather than through the logger) to avoid flooding stderr
+	files := l.(*logger).files
+	require.Len(t, files, 1)
+	line := []byte(strings.Repeat("x", 1023) + "\n")
+	for i := 0; i < 1500; i++ {
+		_, err := files[0].(io.Writer).Write(line)
+		require.NoError(t, err)
+	}
+	require.NoError(t, l.(*logger).Close())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "expected the log file and a single backup")
+
+	info, err := os.Stat(logFile)
+	require.NoError(t, err)
+	assert.Less(t, info.Size(), int64(1024*1024))
+}
+
+func Test_logger_CloseSurfacesErrors(t *testing.T) {
+	l, err := New(Config{
+		FileLocation: filepath.Join(t.TempDir(), "app.log"),
+		Level:        iface.InfoLevel,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, l.(*logger).Close())
+	assert.Error(t, l.(*logger).Close())
+}
+
+func Test_logger_Middleware(t *testing.T) {
+	dir := t.TempDir()
+	errorLog := filepath.Join(dir, "error.log")
+
+	l, err := New(Config{
+		Level:              iface.InfoLevel,
+		Formatter:          &logrus.JSONFormatter{},
+		LevelFileLocations: map[iface.Level]string{iface.ErrorLevel: errorLog},
+		Middleware: []iface.Middleware{
+			func(r iface.Record) (iface.Record, bool) {
+				r.Fields["host"] = "test-host"
+				return r, true
+			},
+			func(r iface.Record) (iface.Record, bool) {
+				return r, !strings.Contains(r.Message, "healthcheck")
+			},
+		},
+	})
+	require.NoError(t, err)
+
+	buff := &bytes.Buffer{}
+	l.(iface.Controller).SetOutput(buff)
+
+	l.Info("healthcheck ok")
+	l.WithFields("key", "value").Info("request handled")
+	l.Error("healthcheck failed")
+
+	lines := strings.Split(strings.TrimSpace(buff.String()), "\n")
+	require.Len(t, lines, 1)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, "request handled", entry["msg"])
+	assert.Equal(t, "value", entry["key"])
+	assert.Equal(t, "test-host", entry["host"])
+
+	// dropped records are dropped from level files too
+	contents, err := os.ReadFile(errorLog)
+	require.NoError(t, err)
+	assert.Empty(t, contents)
+}
+
+// levelRecordingHook records the level of every entry fired
+type levelRecordingHook struct {
+	levels []logrus.Level
+}
+
+func (h *levelRecordingHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *levelRecordingHook) Fire(entry *logrus.Entry) error {
+	h.levels = append(h.levels, entry.Level)
+	return nil
+}
+
+func Test_logger_Log(t *testing.T) {
+	tests := []struct {
+		level iface.Level
+		want  []logrus.Level
+	}{
+		{level: iface.ErrorLevel, want: []logrus.Level{logrus.ErrorLevel}},
+		{level: iface.WarnLevel, want: []logrus.Level{logrus.WarnLevel}},
+		{level: iface.InfoLevel, want: []logrus.Level{logrus.InfoLevel}},
+		{level: iface.DebugLevel, want: []logrus.Level{logrus.DebugLevel}},
+		{level: iface.TraceLevel, want: []logrus.Level{logrus.TraceLevel}},
+		{level: iface.DisabledLevel, want: nil},
+		{level: "bogus", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%q", tt.level), func(t *testing.T) {
+			lr := logrus.New()
+			l, err := Use(lr, Config{Level: iface.TraceLevel})
+			require.NoError(t, err)
+			l.(iface.Controller).SetOutput(io.Discard)
+
+			loggers := map[string]iface.MessageLogger{
+				"logger":      l,
+				"nested":      l.Nested("key", "value"),
+				"with fields": l.WithFields("key", "value"),
+			}
+			for name, ml := range loggers {
+				hook := &levelRecordingHook{}
+				lr.ReplaceHooks(logrus.LevelHooks{})
+				lr.AddHook(hook)
+
+				ml.Log(tt.level, "plain")
+				ml.Logf(tt.level, "formatted %d", 1)
+
+				var want []logrus.Level
+				for _, w := range tt.want {
+					want = append(want, w, w)
+				}
+				assert.Equal(t, want, hook.levels, name)
+			}
+		})
+	}
+}
+
+func Test_logger_CaptureCallerInfo(t *testing.T) {
+	l, err := New(Config{
+		Level:             iface.InfoLevel,
+		Formatter:         DefaultJSONFormatter(),
+		CaptureCallerInfo: true,
+	})
+	require.NoError(t, err)
+
+	buff := &bytes.Buffer{}
+	l.(iface.Controller).SetOutput(buff)
+
+	// callSite returns the file:line of its caller, where the logging call under test is made
+	callSite := func(log func()) string {
+		_, file, line, ok := runtime.Caller(1)
+		require.True(t, ok)
+		log()
+		return fmt.Sprintf("%s:%d", file, line)
+	}
+
+	want := []string{
+		callSite(func() { l.Info("direct") }),
+		callSite(func() { l.Warnf("formatted %d", 1) }),
+		callSite(func() { l.Nested("pkg", "a").Info("nested") }),
+		callSite(func() { l.WithFields("key", "value").Error("with fields") }),
+		callSite(func() { l.Log(iface.InfoLevel, "at level") }),
+	}
+
+	lines := strings.Split(strings.TrimSpace(buff.String()), "\n")
+	require.Len(t, lines, len(want))
+
+	for i, line := range lines {
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		assert.Equal(t, want[i], entry["file"])
+		assert.Contains(t, entry["func"], "Test_logger_CaptureCallerInfo")
+		assert.NotContains(t, entry["file"], "/adapter/logrus/logger.go")
+	}
+}
+
+func Test_logger_CallerExcludedByDefault(t *testing.T) {
+	l, err := New(Config{Level: iface.InfoLevel, Formatter: DefaultJSONFormatter()})
+	require.NoError(t, err)
+
+	buff := &bytes.Buffer{}
+	l.(iface.Controller).SetOutput(buff)
+	l.Info("hello")
+
+	assert.NotContains(t, buff.String(), `"file"`)
+	assert.NotContains(t, buff.String(), `"func"`)
+}
+
+func Test_logger_SensitiveFieldKeys(t *testing.T) {
+	tests := []struct {
+		name      string
+		formatter logrus.Formatter
+	}{
+		{name: "text", formatter: DefaultTextFormatter()},
+		{name: "json", formatter: DefaultJSONFormatter()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := New(Config{
+				Level:              iface.InfoLevel,
+				Formatter:          tt.formatter,
+				SensitiveFieldKeys: []string{"password", "Authorization"},
+			})
+			require.NoError(t, err)
+
+			buff := &bytes.Buffer{}
+			l.(iface.Controller).SetOutput(buff)
+
+			l.WithFields("password", "x", "user", "alice").Info("login")
+			l.Nested("authorization", "Bearer abc123").Info("request")
+
+			got := buff.String()
+			assert.NotContains(t, got, "password=x")
+			assert.NotContains(t, got, `"password":"x"`)
+			assert.NotContains(t, got, "abc123")
+			assert.Contains(t, got, "*******")
+			assert.Contains(t, got, "alice")
+		})
+	}
+}
+
+func Test_logger_Hooks(t *testing.T) {
+	hook := &levelRecordingHook{}
+	l, err := New(Config{
+		Level: iface.InfoLevel,
+		Hooks: []logrus.Hook{hook, nil},
+	})
+	require.NoError(t, err)
+	l.(iface.Controller).SetOutput(io.Discard)
+
+	l.Trace("trace")
+	l.Debug("debug")
+	l.Info("info")
+	l.Nested("pkg", "a").Warn("warn")
+	l.WithFields("key", "value").Error("error")
+
+	// hooks only fire at or above the configured level
+	assert.Equal(t, []logrus.Level{logrus.InfoLevel, logrus.WarnLevel, logrus.ErrorLevel}, hook.levels)
+}
+
+func Test_DefaultConfigForVerbosity(t *testing.T) {
+	tests := []struct {
+		v    int
+		want logrus.Level
+	}{
+		{v: 0, want: logrus.WarnLevel},
+		{v: 1, want: logrus.InfoLevel},
+		{v: 2, want: logrus.DebugLevel},
+		{v: 3, want: logrus.TraceLevel},
+		{v: 4, want: logrus.TraceLevel},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("v=%d", tt.v), func(t *testing.T) {
+			l, err := New(DefaultConfigForVerbosity(tt.v))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, l.(*logger).logger.GetLevel())
+		})
+	}
+}
+
+type contextKey string
+
+func Test_logger_WithContext(t *testing.T) {
+	extractor := func(key string) iface.ContextExtractor {
+		return func(ctx context.Context) (string, interface{}, bool) {
+			v, ok := ctx.Value(contextKey(key)).(string)
+			return key, v, ok
+		}
+	}
+
+	l, err := New(Config{
+		Level:             iface.InfoLevel,
+		Formatter:         DefaultJSONFormatter(),
+		ContextExtractors: []iface.ContextExtractor{extractor("request-id"), extractor("trace-id")},
+	})
+	require.NoError(t, err)
+
+	buff := &bytes.Buffer{}
+	l.(iface.Controller).SetOutput(buff)
+
+	ctx := context.WithValue(context.Background(), contextKey("request-id"), "req-1")
+	ctx = context.WithValue(ctx, contextKey("trace-id"), "trace-1")
+
+	scoped := l.(iface.ContextLogger).WithContext(ctx)
+	scoped.Info("direct")
+	scoped.Nested("pkg", "a").WithFields("key", "value").Info("nested")
+	// contexts can also be attached to loggers that were already nested
+	l.Nested("pkg", "b").(iface.ContextLogger).WithContext(ctx).Info("from nested")
+	l.(iface.ContextLogger).WithContext(context.Background()).Info("missing keys")
+
+	lines := strings.Split(strings.TrimSpace(buff.String()), "\n")
+	require.Len(t, lines, 4)
+
+	var entries []map[string]interface{}
+	for _, line := range lines {
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		delete(entry, "time")
+		entries = append(entries, entry)
+	}
+
+	assert.Equal(t, []map[string]interface{}{
+		{"level": "info", "msg": "direct", "request-id": "req-1", "trace-id": "trace-1"},
+		{"level": "info", "msg": "nested", "request-id": "req-1", "trace-id": "trace-1", "pkg": "a", "key": "value"},
+		{"level": "info", "msg": "from nested", "request-id": "req-1", "trace-id": "trace-1", "pkg": "b"},
+		{"level": "info", "msg": "missing keys"},
+	}, entries)
+}
+
+func Test_logger_NoColor(t *testing.T) {
+	const escape = "\x1b["
+
+	tests := []struct {
+		name      string
+		noColor   bool
+		wantColor bool
+	}{
+		{name: "colors forced", wantColor: true},
+		{name: "colors disabled", noColor: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errorLog := filepath.Join(t.TempDir(), "error.log")
+			formatter := &TextFormatter{ForceColors: true, ForceFormatting: true}
+			l, err := New(Config{
+				Level:              iface.InfoLevel,
+				Formatter:          formatter,
+				NoColor:            tt.noColor,
+				LevelFileLocations: map[iface.Level]string{iface.ErrorLevel: errorLog},
+				Middleware: []iface.Middleware{
+					func(r iface.Record) (iface.Record, bool) { return r, true },
+				},
+			})
+			require.NoError(t, err)
+
+			buff := &bytes.Buffer{}
+			l.(iface.Controller).SetOutput(buff)
+			l.WithFields("key", "value").Error("[prefix] failed")
+
+			if tt.wantColor {
+				assert.Contains(t, buff.String(), escape)
+			} else {
+				assert.NotContains(t, buff.String(), escape)
+			}
+			assert.Contains(t, buff.String(), "failed")
+			// the configured formatter may be shared with other loggers, so must be left as-is
+			assert.False(t, formatter.DisableColors)
+
+			// level files are never colored
+			contents, err := os.ReadFile(errorLog)
+			require.NoError(t, err)
+			assert.Contains(t, string(contents), "failed")
+			assert.NotContains(t, string(contents), escape)
+		})
+	}
+}
+
+func Test_logger_MaxFields(t *testing.T) {
+	l, err := New(Config{
+		Level:         iface.InfoLevel,
+		Formatter:     DefaultJSONFormatter(),
+		MaxFields:     3,
+		IncludeUptime: true,
+	})
+	require.NoError(t, err)
+
+	buff := &bytes.Buffer{}
+	l.(iface.Controller).SetOutput(buff)
+
+	nested := l.Nested("e", 5, "d", 4)
+	nested.WithFields("c", 3, "b", 2, "a", 1).Info("too many")
+	nested.WithFields("a", 1).Info("at the cap")
+
+	lines := strings.Split(strings.TrimSpace(buff.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var entries []map[string]interface{}
+	for _, line := range lines {
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		delete(entry, "time")
+		delete(entry, "uptime")
+		entries = append(entries, entry)
+	}
+
+	assert.Equal(t, []map[string]interface{}{
+		{"level": "info", "msg": "too many", "a": float64(1), "b": float64(2), "c": float64(3), "fields_truncated": true},
+		{"level": "info", "msg": "at the cap", "a": float64(1), "d": float64(4), "e": float64(5)},
+	}, entries)
+	assert.Contains(t, lines[0], `"uptime"`)
+}
+
+func Test_logger_ErrorStackDepth(t *testing.T) {
+	l, err := New(Config{
+		Level:           iface.InfoLevel,
+		Formatter:       DefaultJSONFormatter(),
+		ErrorStackDepth: 2,
+	})
+	require.NoError(t, err)
+
+	buff := &bytes.Buffer{}
+	l.(iface.Controller).SetOutput(buff)
+
+	l.Info("working")
+	l.Nested("pkg", "a").Errorf("failed: %s", "boom")
+
+	lines := strings.Split(strings.TrimSpace(buff.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var info, failure map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &info))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &failure))
+
+	assert.NotContains(t, info, "stack")
+
+	require.Contains(t, failure, "stack")
+	stack := failure["stack"].([]interface{})
+	require.Len(t, stack, 2)
+	// the innermost frame is the call site, not this adapter
+	assert.Contains(t, stack[0], "Test_logger_ErrorStackDepth")
+	assert.Contains(t, stack[0], "logger_test.go:")
+}
+
+func Test_logger_ErrorStackExcludedByDefault(t *testing.T) {
+	l, err := New(Config{Level: iface.InfoLevel, Formatter: DefaultJSONFormatter()})
+	require.NoError(t, err)
+
+	buff := &bytes.Buffer{}
+	l.(iface.Controller).SetOutput(buff)
+	l.Error("failed")
+
+	assert.NotContains(t, buff.String(), "stack")
+}
+
+func Test_logger_Clone(t *testing.T) {
+	l, err := New(Config{
+		Level:     iface.DebugLevel,
+		Formatter: DefaultJSONFormatter(),
+	})
+	require.NoError(t, err)
+	parentOut := &bytes.Buffer{}
+	l.(iface.Controller).SetOutput(parentOut)
+
+	tests := []struct {
+		name   string
+		parent iface.Logger
+		want   []string
+	}{
+		{
+			name:   "logger",
+			parent: l,
+			want:   []string{`"level":"debug"`, `"msg":"from the clone"`},
+		},
+		{
+			name:   "nested logger",
+			parent: l.Nested("pkg", "a"),
+			want:   []string{`"level":"debug"`, `"msg":"from the clone"`, `"pkg":"a"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parentOut.Reset()
+
+			cloned := tt.parent.(iface.Cloner).Clone()
+			clonedOut := &bytes.Buffer{}
+			cloned.(iface.Controller).SetOutput(clonedOut)
+			assert.Equal(t, clonedOut, cloned.(iface.Controller).GetOutput())
+
+			cloned.Debug("from the clone")
+			tt.parent.Info("from the parent")
+
+			for _, w := range tt.want {
+				assert.Contains(t, clonedOut.String(), w)
+			}
+			assert.NotContains(t, clonedOut.String(), "from the parent")
+
+			// the parent output is unchanged
+			assert.Equal(t, parentOut, l.(iface.Controller).GetOutput())
+			assert.Contains(t, parentOut.String(), "from the parent")
+			assert.NotContains(t, parentOut.String(), "from the clone")
+		})
+	}
+}
+
+func Test_logger_IsLevelEnabled(t *testing.T) {
+	l, err := New(Config{Level: iface.InfoLevel})
+	require.NoError(t, err)
+
+	for _, ll := range []iface.Logger{l, l.Nested("key", "value")} {
+		e, ok := ll.(iface.LevelEnabler)
+		require.True(t, ok)
+		assert.True(t, e.IsLevelEnabled(iface.ErrorLevel))
+		assert.True(t, e.IsLevelEnabled(iface.InfoLevel))
+		assert.False(t, e.IsLevelEnabled(iface.DebugLevel))
+		assert.False(t, e.IsLevelEnabled("bogus"))
+	}
+}
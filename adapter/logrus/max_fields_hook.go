@@ -0,0 +1,39 @@
+package logrus
+
+import (
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+// fieldsTruncatedField is the field name set on entries that had fields dropped due to Config.MaxFields
+const fieldsTruncatedField = "fields_truncated"
+
+var _ logrus.Hook = (*maxFieldsHook)(nil)
+
+// maxFieldsHook caps the number of fields on each entry, keeping the first fields by key (so the same fields are always
+// kept for the same set of keys) and marking entries that had fields dropped
+type maxFieldsHook struct {
+	max int
+}
+
+func (h maxFieldsHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h maxFieldsHook) Fire(entry *logrus.Entry) error {
+	if len(entry.Data) <= h.max {
+		return nil
+	}
+	keys := make([]string, 0, len(entry.Data))
+	for k := range entry.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	// note: the entry data is a copy made by logrus for this entry, so the logger's fields are not modified
+	for _, k := range keys[h.max:] {
+		delete(entry.Data, k)
+	}
+	entry.Data[fieldsTruncatedField] = true
+	return nil
+}
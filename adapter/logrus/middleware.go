@@ -0,0 +1,92 @@
+package logrus
+
+import (
+	"github.com/sirupsen/logrus"
+
+	iface "github.com/anchore/go-logger"
+)
+
+var _ logrus.Formatter = (*middlewareFormatter)(nil)
+var _ uncoloredFormatter = (*middlewareFormatter)(nil)
+
+// middlewareFormatter applies middleware to each entry before formatting it with the wrapped formatter. Logrus hooks
+// cannot prevent an entry from being written, so middleware is applied at formatting time instead, where a dropped
+// entry is formatted as nothing at all. The original entry is never modified, since an entry may be formatted more
+// than once (e.g. for level files).
+type middlewareFormatter struct {
+	formatter  logrus.Formatter
+	middleware []iface.Middleware
+}
+
+func newMiddlewareFormatter(formatter logrus.Formatter, middleware []iface.Middleware) *middlewareFormatter {
+	return &middlewareFormatter{
+		formatter:  formatter,
+		middleware: middleware,
+	}
+}
+
+func (m *middlewareFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	modified, keep := m.apply(entry)
+	if !keep {
+		return nil, nil
+	}
+	return m.formatter.Format(modified)
+}
+
+func (m *middlewareFormatter) formatUncolored(entry *logrus.Entry) ([]byte, error) {
+	modified, keep := m.apply(entry)
+	if !keep {
+		return nil, nil
+	}
+	return formatUncolored(m.formatter, modified)
+}
+
+// apply returns a copy of the entry with all middleware applied, or false if the entry was dropped
+func (m *middlewareFormatter) apply(entry *logrus.Entry) (*logrus.Entry, bool) {
+	fields := make(iface.Fields, len(entry.Data))
+	for k, v := range entry.Data {
+		fields[k] = v
+	}
+
+	level := getIfaceLevel(entry.Level)
+	record, keep := iface.ApplyMiddleware(iface.Record{
+		Time:    entry.Time,
+		Level:   level,
+		Message: entry.Message,
+		Fields:  fields,
+	}, m.middleware...)
+	if !keep {
+		return nil, false
+	}
+
+	modified := entry.Dup()
+	modified.Time = record.Time
+	modified.Level = entry.Level
+	if record.Level != level {
+		modified.Level = getLogLevel(record.Level)
+	}
+	modified.Message = record.Message
+	modified.Caller = entry.Caller
+	modified.Buffer = entry.Buffer
+	modified.Data = make(logrus.Fields, len(record.Fields))
+	for k, v := range record.Fields {
+		modified.Data[k] = v
+	}
+	return modified, true
+}
+
+func getIfaceLevel(level logrus.Level) iface.Level {
+	switch level {
+	case logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel:
+		return iface.ErrorLevel
+	case logrus.WarnLevel:
+		return iface.WarnLevel
+	case logrus.InfoLevel:
+		return iface.InfoLevel
+	case logrus.DebugLevel:
+		return iface.DebugLevel
+	case logrus.TraceLevel:
+		return iface.TraceLevel
+	}
+	return iface.InfoLevel
+}
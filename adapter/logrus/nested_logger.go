@@ -1,16 +1,22 @@
 package logrus
 
 import (
+	"context"
+
 	"github.com/sirupsen/logrus"
 
 	iface "github.com/anchore/go-logger"
 )
 
 var _ iface.Logger = (*nestedLogger)(nil)
+var _ iface.ContextLogger = (*nestedLogger)(nil)
+var _ iface.LevelEnabler = (*nestedLogger)(nil)
 
 // nestedLogger is a wrapper for Logrus to enable nested logging configuration (loggers that always attach key-value pairs to all log entries)
 type nestedLogger struct {
 	entry *logrus.Entry
+	// extractors are the context extractors of the logger this was nested from
+	extractors []iface.ContextExtractor
 }
 
 // Tracef takes a formatted template string and template arguments for the trace logging level.
@@ -63,11 +69,31 @@ func (l *nestedLogger) Error(args ...interface{}) {
 	l.entry.Error(args...)
 }
 
+// Logf takes a formatted template string and template arguments for the given logging level.
+func (l *nestedLogger) Logf(level iface.Level, format string, args ...interface{}) {
+	iface.LogfAtLevel(l, level, format, args...)
+}
+
+// Log logs the given arguments at the given logging level.
+func (l *nestedLogger) Log(level iface.Level, args ...interface{}) {
+	iface.LogAtLevel(l, level, args...)
+}
+
 // WithFields returns a message entry with multiple key-value fields.
 func (l *nestedLogger) WithFields(fields ...interface{}) iface.MessageLogger {
-	return l.entry.WithFields(getFields(fields...))
+	return &nestedLogger{entry: l.entry.WithFields(logrus.Fields(iface.FieldsFrom(fields...))), extractors: l.extractors}
 }
 
 func (l *nestedLogger) Nested(fields ...interface{}) iface.Logger {
-	return &nestedLogger{entry: l.entry.WithFields(getFields(fields...))}
+	return &nestedLogger{entry: l.entry.WithFields(logrus.Fields(iface.FieldsFrom(fields...))), extractors: l.extractors}
+}
+
+// WithContext returns a logger nested with all fields found in the context by the configured ContextExtractors.
+func (l *nestedLogger) WithContext(ctx context.Context) iface.Logger {
+	return l.Nested(iface.ContextFields(ctx, l.extractors...)...)
+}
+
+// IsLevelEnabled reports whether messages at the given level are logged.
+func (l *nestedLogger) IsLevelEnabled(level iface.Level) bool {
+	return isLevelEnabled(l.entry.Logger, level)
 }
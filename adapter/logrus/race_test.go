@@ -0,0 +1,6 @@
+//go:build race
+
+package logrus
+
+// raceEnabled is true when the race detector is enabled, which adds allocations of its own
+const raceEnabled = true
@@ -0,0 +1,45 @@
+package logrus
+
+import (
+	"fmt"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+var _ OutputDescriber = (*rotatingFile)(nil)
+
+// rotatingFile is a log file that is rotated once it reaches a maximum size
+type rotatingFile struct {
+	*lumberjack.Logger
+}
+
+func (r *rotatingFile) DescribeOutput() []string {
+	return []string{"rotating-file:" + r.Filename}
+}
+
+// rotationEnabled reports whether any rotation settings have been configured
+func (cfg Config) rotationEnabled() bool {
+	return cfg.MaxSizeMB > 0 || cfg.MaxBackups > 0 || cfg.MaxAgeDays > 0 || cfg.Compress
+}
+
+func openRotatingFile(location string, cfg Config) (*rotatingFile, error) {
+	// create the file up front so that errors (e.g. permissions) are reported immediately rather than on the first
+	// write, and so that the configured permissions are applied (rotated files keep the permissions of the original)
+	f, err := openFile(location, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("unable to close log file: %w", err)
+	}
+
+	return &rotatingFile{
+		Logger: &lumberjack.Logger{
+			Filename:   location,
+			MaxSize:    cfg.MaxSizeMB,
+			MaxBackups: cfg.MaxBackups,
+			MaxAge:     cfg.MaxAgeDays,
+			Compress:   cfg.Compress,
+		},
+	}, nil
+}
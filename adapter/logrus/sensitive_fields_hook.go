@@ -0,0 +1,40 @@
+package logrus
+
+import (
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maskedFieldValue replaces the value of any sensitive field (matching the marker used by the redact adapter)
+const maskedFieldValue = "*******"
+
+var _ logrus.Hook = (*sensitiveFieldsHook)(nil)
+
+// sensitiveFieldsHook masks the values of fields with sensitive keys, regardless of the value
+type sensitiveFieldsHook struct {
+	// keys are the lowercased sensitive field keys
+	keys map[string]struct{}
+}
+
+func newSensitiveFieldsHook(keys []string) *sensitiveFieldsHook {
+	h := &sensitiveFieldsHook{keys: make(map[string]struct{}, len(keys))}
+	for _, k := range keys {
+		h.keys[strings.ToLower(k)] = struct{}{}
+	}
+	return h
+}
+
+func (h *sensitiveFieldsHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *sensitiveFieldsHook) Fire(entry *logrus.Entry) error {
+	// note: the entry data is a copy made by logrus for this entry, so the logger's fields are not modified
+	for k := range entry.Data {
+		if _, ok := h.keys[strings.ToLower(k)]; ok {
+			entry.Data[k] = maskedFieldValue
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package logrus
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/sirupsen/logrus"
+)
+
+// stackField is the field name the stack trace is attached under for error entries
+const stackField = "stack"
+
+var _ logrus.Hook = (*stackHook)(nil)
+
+// stackHook attaches the stack trace of the call site (as "function file:line" frames, innermost first) to all entries
+// at error level or above
+type stackHook struct {
+	depth int
+}
+
+func (h stackHook) Levels() []logrus.Level {
+	return []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel}
+}
+
+func (h stackHook) Fire(entry *logrus.Entry) error {
+	entry.Data[stackField] = captureStack(h.depth)
+	return nil
+}
+
+// captureStack returns up to depth frames of the current stack, starting at the first frame outside of logrus and
+// go-logger
+func captureStack(depth int) []string {
+	pcs := make([]uintptr, depth+maxCallerDepth)
+	// skip runtime.Callers and captureStack
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	var stack []string
+	skipping := true
+	for len(stack) < depth {
+		frame, more := frames.Next()
+		if skipping && isLoggingFrame(frame) {
+			if !more {
+				break
+			}
+			continue
+		}
+		skipping = false
+		stack = append(stack, fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line))
+		if !more {
+			break
+		}
+	}
+	return stack
+}
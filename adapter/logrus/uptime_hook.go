@@ -0,0 +1,33 @@
+package logrus
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// uptimeField is the field name the elapsed time since logger construction is attached under
+const uptimeField = "uptime"
+
+// now is the clock used for uptime calculations (replaceable for testing)
+var now = time.Now
+
+var _ logrus.Hook = (*uptimeHook)(nil)
+
+// uptimeHook attaches the elapsed time since the logger was constructed to every entry
+type uptimeHook struct {
+	start time.Time
+}
+
+func newUptimeHook() *uptimeHook {
+	return &uptimeHook{start: now()}
+}
+
+func (h *uptimeHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *uptimeHook) Fire(entry *logrus.Entry) error {
+	entry.Data[uptimeField] = now().Sub(h.start)
+	return nil
+}
@@ -0,0 +1,166 @@
+package memory
+
+import (
+	"fmt"
+	"sync"
+
+	iface "github.com/anchore/go-logger"
+)
+
+var _ Logger = (*logger)(nil)
+
+// Entry is a single captured log entry
+type Entry struct {
+	Level   iface.Level
+	Message string
+	// Fields are all key-value fields attached to the entry (from WithFields and Nested)
+	Fields iface.Fields
+}
+
+// Logger is an iface.Logger that captures all entries in memory (at every level), intended for asserting on what
+// code under test has logged.
+type Logger interface {
+	iface.Logger
+	// Entries returns a copy of all entries captured so far (by this logger and all loggers nested from it), in the
+	// order they were logged.
+	Entries() []Entry
+	// Reset discards all captured entries.
+	Reset()
+}
+
+// entries are the captured entries shared by a logger and all loggers nested from it
+type entries struct {
+	captured []Entry
+	lock     sync.Mutex
+}
+
+type logger struct {
+	entries *entries
+	fields  iface.Fields
+}
+
+// New creates a logger that captures all entries in memory.
+func New() Logger {
+	return &logger{
+		entries: &entries{},
+	}
+}
+
+// Tracef takes a formatted template string and template arguments for the trace logging level.
+func (l *logger) Tracef(format string, args ...interface{}) {
+	l.capture(iface.TraceLevel, fmt.Sprintf(format, args...))
+}
+
+// Debugf takes a formatted template string and template arguments for the debug logging level.
+func (l *logger) Debugf(format string, args ...interface{}) {
+	l.capture(iface.DebugLevel, fmt.Sprintf(format, args...))
+}
+
+// Infof takes a formatted template string and template arguments for the info logging level.
+func (l *logger) Infof(format string, args ...interface{}) {
+	l.capture(iface.InfoLevel, fmt.Sprintf(format, args...))
+}
+
+// Warnf takes a formatted template string and template arguments for the warning logging level.
+func (l *logger) Warnf(format string, args ...interface{}) {
+	l.capture(iface.WarnLevel, fmt.Sprintf(format, args...))
+}
+
+// Errorf takes a formatted template string and template arguments for the error logging level.
+func (l *logger) Errorf(format string, args ...interface{}) {
+	l.capture(iface.ErrorLevel, fmt.Sprintf(format, args...))
+}
+
+// Trace logs the given arguments at the trace logging level.
+func (l *logger) Trace(args ...interface{}) {
+	l.capture(iface.TraceLevel, fmt.Sprint(args...))
+}
+
+// Debug logs the given arguments at the debug logging level.
+func (l *logger) Debug(args ...interface{}) {
+	l.capture(iface.DebugLevel, fmt.Sprint(args...))
+}
+
+// Info logs the given arguments at the info logging level.
+func (l *logger) Info(args ...interface{}) {
+	l.capture(iface.InfoLevel, fmt.Sprint(args...))
+}
+
+// Warn logs the given arguments at the warning logging level.
+func (l *logger) Warn(args ...interface{}) {
+	l.capture(iface.WarnLevel, fmt.Sprint(args...))
+}
+
+// Error logs the given arguments at the error logging level.
+func (l *logger) Error(args ...interface{}) {
+	l.capture(iface.ErrorLevel, fmt.Sprint(args...))
+}
+
+// Logf takes a formatted template string and template arguments for the given logging level.
+func (l *logger) Logf(level iface.Level, format string, args ...interface{}) {
+	iface.LogfAtLevel(l, level, format, args...)
+}
+
+// Log logs the given arguments at the given logging level.
+func (l *logger) Log(level iface.Level, args ...interface{}) {
+	iface.LogAtLevel(l, level, args...)
+}
+
+// WithFields returns a message logger with multiple key-value fields.
+func (l *logger) WithFields(fields ...interface{}) iface.MessageLogger {
+	return l.with(fields...)
+}
+
+// Nested returns a logger that attaches the given key-value fields (along with any from this logger) to all entries.
+func (l *logger) Nested(fields ...interface{}) iface.Logger {
+	return l.with(fields...)
+}
+
+func (l *logger) Entries() []Entry {
+	l.entries.lock.Lock()
+	defer l.entries.lock.Unlock()
+	result := make([]Entry, 0, len(l.entries.captured))
+	for _, e := range l.entries.captured {
+		e.Fields = copyFields(e.Fields)
+		result = append(result, e)
+	}
+	return result
+}
+
+func (l *logger) Reset() {
+	l.entries.lock.Lock()
+	defer l.entries.lock.Unlock()
+	l.entries.captured = nil
+}
+
+func (l *logger) with(fields ...interface{}) *logger {
+	merged := copyFields(l.fields)
+	for k, v := range iface.FieldsFrom(fields...) {
+		merged[k] = v
+	}
+	return &logger{
+		entries: l.entries,
+		fields:  merged,
+	}
+}
+
+func (l *logger) capture(level iface.Level, msg string) {
+	// each entry gets its own copy of the fields, so that entries cannot be modified through one another
+	fields := copyFields(l.fields)
+
+	l.entries.lock.Lock()
+	defer l.entries.lock.Unlock()
+	l.entries.captured = append(l.entries.captured, Entry{
+		Level:   level,
+		Message: msg,
+		Fields:  fields,
+	})
+}
+
+func copyFields(fields iface.Fields) iface.Fields {
+	c := make(iface.Fields, len(fields))
+	for k, v := range fields {
+		c[k] = v
+	}
+	return c
+}
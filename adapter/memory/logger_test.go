@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	iface "github.com/anchore/go-logger"
+)
+
+func TestLogger_Entries(t *testing.T) {
+	l := New()
+
+	l.Info("starting")
+	nested := l.Nested("pkg", "db")
+	nested.WithFields("table", "users", iface.Fields{"rows": 3}).Debugf("loaded %d rows", 3)
+	nested.Nested("pkg", "db/migrate").Warn("slow ", "migration")
+	l.Tracef("done")
+	l.Error("failed")
+
+	assert.Equal(t, []Entry{
+		{Level: iface.InfoLevel, Message: "starting", Fields: iface.Fields{}},
+		{Level: iface.DebugLevel, Message: "loaded 3 rows", Fields: iface.Fields{"pkg": "db", "table": "users", "rows": 3}},
+		{Level: iface.WarnLevel, Message: "slow migration", Fields: iface.Fields{"pkg": "db/migrate"}},
+		{Level: iface.TraceLevel, Message: "done", Fields: iface.Fields{}},
+		{Level: iface.ErrorLevel, Message: "failed", Fields: iface.Fields{}},
+	}, l.Entries())
+
+	// modifying returned entries does not affect captured entries
+	entries := l.Entries()
+	entries[0].Fields["mutated"] = true
+	assert.Equal(t, iface.Fields{}, l.Entries()[0].Fields)
+
+	l.Reset()
+	assert.Empty(t, l.Entries())
+}
+
+func TestLogger_Concurrent(t *testing.T) {
+	l := New()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			nested := l.Nested("worker", i)
+			for j := 0; j < 100; j++ {
+				nested.Infof("message %d", j)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	entries := l.Entries()
+	require.Len(t, entries, 1000)
+
+	counts := make(map[string]int)
+	for _, e := range entries {
+		counts[fmt.Sprint(e.Fields["worker"])]++
+	}
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, 100, counts[fmt.Sprint(i)])
+	}
+}
@@ -0,0 +1,184 @@
+package protostream
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	iface "github.com/anchore/go-logger"
+)
+
+var _ iface.Logger = (*logger)(nil)
+var _ iface.Controller = (*logger)(nil)
+
+// now is the clock used to timestamp records (replaceable for testing)
+var now = time.Now
+
+// output is the destination shared by a logger and all loggers nested from it
+type output struct {
+	writer io.Writer
+	lock   sync.Mutex
+}
+
+// logger writes each entry as a length-delimited LogRecord protobuf message (see logrecord.proto)
+type logger struct {
+	output *output
+	level  iface.Level
+	fields []Field
+}
+
+// New creates a logger that writes entries at or above the given level to the given writer as length-delimited
+// LogRecord protobuf messages. Use NewDecoder to read the records back.
+func New(w io.Writer, level iface.Level) iface.Logger {
+	return &logger{
+		output: &output{writer: w},
+		level:  level,
+	}
+}
+
+// Tracef takes a formatted template string and template arguments for the trace logging level.
+func (l *logger) Tracef(format string, args ...interface{}) {
+	l.logf(iface.TraceLevel, format, args...)
+}
+
+// Debugf takes a formatted template string and template arguments for the debug logging level.
+func (l *logger) Debugf(format string, args ...interface{}) {
+	l.logf(iface.DebugLevel, format, args...)
+}
+
+// Infof takes a formatted template string and template arguments for the info logging level.
+func (l *logger) Infof(format string, args ...interface{}) {
+	l.logf(iface.InfoLevel, format, args...)
+}
+
+// Warnf takes a formatted template string and template arguments for the warning logging level.
+func (l *logger) Warnf(format string, args ...interface{}) {
+	l.logf(iface.WarnLevel, format, args...)
+}
+
+// Errorf takes a formatted template string and template arguments for the error logging level.
+func (l *logger) Errorf(format string, args ...interface{}) {
+	l.logf(iface.ErrorLevel, format, args...)
+}
+
+// Trace logs the given arguments at the trace logging level.
+func (l *logger) Trace(args ...interface{}) {
+	l.log(iface.TraceLevel, args...)
+}
+
+// Debug logs the given arguments at the debug logging level.
+func (l *logger) Debug(args ...interface{}) {
+	l.log(iface.DebugLevel, args...)
+}
+
+// Info logs the given arguments at the info logging level.
+func (l *logger) Info(args ...interface{}) {
+	l.log(iface.InfoLevel, args...)
+}
+
+// Warn logs the given arguments at the warning logging level.
+func (l *logger) Warn(args ...interface{}) {
+	l.log(iface.WarnLevel, args...)
+}
+
+// Error logs the given arguments at the error logging level.
+func (l *logger) Error(args ...interface{}) {
+	l.log(iface.ErrorLevel, args...)
+}
+
+// Logf takes a formatted template string and template arguments for the given logging level.
+func (l *logger) Logf(level iface.Level, format string, args ...interface{}) {
+	iface.LogfAtLevel(l, level, format, args...)
+}
+
+// Log logs the given arguments at the given logging level.
+func (l *logger) Log(level iface.Level, args ...interface{}) {
+	iface.LogAtLevel(l, level, args...)
+}
+
+// WithFields returns a message logger with multiple key-value fields.
+func (l *logger) WithFields(fields ...interface{}) iface.MessageLogger {
+	return l.with(fields...)
+}
+
+// Nested returns a logger that attaches the given key-value fields (along with any from this logger) to all records.
+func (l *logger) Nested(fields ...interface{}) iface.Logger {
+	return l.with(fields...)
+}
+
+func (l *logger) SetOutput(writer io.Writer) {
+	l.output.lock.Lock()
+	defer l.output.lock.Unlock()
+	l.output.writer = writer
+}
+
+func (l *logger) GetOutput() io.Writer {
+	l.output.lock.Lock()
+	defer l.output.lock.Unlock()
+	return l.output.writer
+}
+
+func (l *logger) with(fields ...interface{}) *logger {
+	merged := make([]Field, 0, len(l.fields)+len(fields)/2)
+	merged = append(merged, l.fields...)
+	merged = append(merged, getFields(fields...)...)
+	return &logger{
+		output: l.output,
+		level:  l.level,
+		fields: merged,
+	}
+}
+
+func (l *logger) logf(level iface.Level, format string, args ...interface{}) {
+	if !l.enabled(level) {
+		return
+	}
+	l.write(level, fmt.Sprintf(format, args...))
+}
+
+func (l *logger) log(level iface.Level, args ...interface{}) {
+	if !l.enabled(level) {
+		return
+	}
+	l.write(level, fmt.Sprint(args...))
+}
+
+func (l *logger) write(level iface.Level, msg string) {
+	data := Record{
+		Time:    now(),
+		Level:   level,
+		Message: msg,
+		Fields:  l.fields,
+	}.marshal()
+
+	l.output.lock.Lock()
+	defer l.output.lock.Unlock()
+	// note: like other adapters, write errors are not surfaced to the caller
+	_, _ = l.output.writer.Write(data)
+}
+
+// enabled reports whether entries at the given level should be written, based on the configured level
+func (l *logger) enabled(level iface.Level) bool {
+	configured := levelIndex(l.level)
+	return configured >= 0 && levelIndex(level) <= configured
+}
+
+// levelIndex returns the verbosity of the level (0 being the least verbose), or -1 if not a loggable level
+func levelIndex(level iface.Level) int {
+	for i, l := range iface.Levels() {
+		if l == level {
+			return i
+		}
+	}
+	return -1
+}
+
+// getFields converts key-value pairs (and any iface.Fields maps found among them) into record fields
+func getFields(fields ...interface{}) []Field {
+	var result []Field
+	iface.RangeFields(fields, func(key string, val interface{}) {
+		result = append(result, Field{Key: key, Value: fmt.Sprintf("%+v", val)})
+	})
+	return result
+}
@@ -0,0 +1,105 @@
+package protostream
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	iface "github.com/anchore/go-logger"
+)
+
+func TestLogger_RoundTrip(t *testing.T) {
+	current := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	original := now
+	now = func() time.Time { return current }
+	t.Cleanup(func() { now = original })
+
+	buff := &bytes.Buffer{}
+	l := New(buff, iface.DebugLevel)
+
+	l.Trace("filtered")
+	l.Debugf("debug %d", 1)
+	l.Info("info")
+	current = current.Add(time.Second)
+	nested := l.Nested("pkg", "a", iface.Fields{"count": 2})
+	nested.WithFields("file", "b").Warn("warn")
+	nested.Errorf("error %s", "ü")
+
+	dec := NewDecoder(buff)
+	var got []Record
+	for {
+		r, err := dec.Decode()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		got = append(got, r)
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	want := []Record{
+		{Time: start, Level: iface.DebugLevel, Message: "debug 1"},
+		{Time: start, Level: iface.InfoLevel, Message: "info"},
+		{Time: start.Add(time.Second), Level: iface.WarnLevel, Message: "warn", Fields: []Field{
+			{Key: "pkg", Value: "a"}, {Key: "count", Value: "2"}, {Key: "file", Value: "b"},
+		}},
+		{Time: start.Add(time.Second), Level: iface.ErrorLevel, Message: "error ü", Fields: []Field{
+			{Key: "pkg", Value: "a"}, {Key: "count", Value: "2"},
+		}},
+	}
+	require.Len(t, got, len(want))
+	for i := range want {
+		assert.True(t, want[i].Time.Equal(got[i].Time), "record %d time: %s", i, got[i].Time)
+		got[i].Time = want[i].Time
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestLogger_LevelFiltering(t *testing.T) {
+	tests := []struct {
+		level iface.Level
+		want  []iface.Level
+	}{
+		{level: iface.TraceLevel, want: []iface.Level{iface.ErrorLevel, iface.WarnLevel, iface.InfoLevel, iface.DebugLevel, iface.TraceLevel}},
+		{level: iface.WarnLevel, want: []iface.Level{iface.ErrorLevel, iface.WarnLevel}},
+		{level: iface.DisabledLevel, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.level), func(t *testing.T) {
+			buff := &bytes.Buffer{}
+			l := New(buff, tt.level)
+			l.Error("e")
+			l.Warn("w")
+			l.Info("i")
+			l.Debug("d")
+			l.Trace("t")
+
+			var got []iface.Level
+			dec := NewDecoder(buff)
+			for {
+				r, err := dec.Decode()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				require.NoError(t, err)
+				got = append(got, r.Level)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDecoder_TruncatedRecord(t *testing.T) {
+	buff := &bytes.Buffer{}
+	New(buff, iface.InfoLevel).Info("hello")
+
+	truncated := buff.Bytes()[:buff.Len()-2]
+	_, err := NewDecoder(bytes.NewReader(truncated)).Decode()
+	require.Error(t, err)
+	assert.False(t, errors.Is(err, io.EOF))
+}
@@ -0,0 +1,181 @@
+package protostream
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	iface "github.com/anchore/go-logger"
+)
+
+// field numbers and wire types as defined in logrecord.proto
+const (
+	wireVarint = 0
+	wireBytes  = 2
+
+	recordTimestampField = 1
+	recordLevelField     = 2
+	recordMessageField   = 3
+	recordFieldsField    = 4
+
+	fieldKeyField   = 1
+	fieldValueField = 2
+
+	// maxRecordSize bounds the size of a single decoded record to guard against corrupt length prefixes
+	maxRecordSize = 64 << 20
+)
+
+// Record is a single decoded LogRecord
+type Record struct {
+	Time    time.Time
+	Level   iface.Level
+	Message string
+	Fields  []Field
+}
+
+// Field is a single key-value pair attached to a Record
+type Field struct {
+	Key   string
+	Value string
+}
+
+var levelNumbers = map[iface.Level]uint64{
+	iface.ErrorLevel: 1,
+	iface.WarnLevel:  2,
+	iface.InfoLevel:  3,
+	iface.DebugLevel: 4,
+	iface.TraceLevel: 5,
+}
+
+// marshal encodes the record as a length-delimited LogRecord message
+func (r Record) marshal() []byte {
+	var msg []byte
+	msg = appendVarintField(msg, recordTimestampField, uint64(r.Time.UnixNano()))
+	msg = appendVarintField(msg, recordLevelField, levelNumbers[r.Level])
+	msg = appendBytesField(msg, recordMessageField, []byte(r.Message))
+	for _, f := range r.Fields {
+		var field []byte
+		field = appendBytesField(field, fieldKeyField, []byte(f.Key))
+		field = appendBytesField(field, fieldValueField, []byte(f.Value))
+		msg = appendBytesField(msg, recordFieldsField, field)
+	}
+
+	out := binary.AppendUvarint(make([]byte, 0, len(msg)+binary.MaxVarintLen64), uint64(len(msg)))
+	return append(out, msg...)
+}
+
+func appendVarintField(b []byte, num int, v uint64) []byte {
+	b = binary.AppendUvarint(b, uint64(num)<<3|wireVarint)
+	return binary.AppendUvarint(b, v)
+}
+
+func appendBytesField(b []byte, num int, v []byte) []byte {
+	b = binary.AppendUvarint(b, uint64(num)<<3|wireBytes)
+	b = binary.AppendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+// Decoder reads length-delimited LogRecord messages from a stream
+type Decoder struct {
+	reader *bufio.Reader
+}
+
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{reader: bufio.NewReader(r)}
+}
+
+// Decode reads the next record from the stream, returning io.EOF when there are no more records.
+func (d *Decoder) Decode() (Record, error) {
+	size, err := binary.ReadUvarint(d.reader)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return Record{}, io.EOF
+		}
+		return Record{}, fmt.Errorf("unable to read record length: %w", err)
+	}
+	if size > maxRecordSize {
+		return Record{}, fmt.Errorf("record length %d exceeds maximum of %d", size, maxRecordSize)
+	}
+
+	msg := make([]byte, size)
+	if _, err := io.ReadFull(d.reader, msg); err != nil {
+		return Record{}, fmt.Errorf("unable to read record: %w", err)
+	}
+	return unmarshalRecord(msg)
+}
+
+func unmarshalRecord(msg []byte) (Record, error) {
+	var r Record
+	err := walkFields(msg, func(num int, v uint64, b []byte) error {
+		switch num {
+		case recordTimestampField:
+			r.Time = time.Unix(0, int64(v))
+		case recordLevelField:
+			for l, n := range levelNumbers {
+				if n == v {
+					r.Level = l
+				}
+			}
+		case recordMessageField:
+			r.Message = string(b)
+		case recordFieldsField:
+			var f Field
+			err := walkFields(b, func(num int, _ uint64, b []byte) error {
+				switch num {
+				case fieldKeyField:
+					f.Key = string(b)
+				case fieldValueField:
+					f.Value = string(b)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			r.Fields = append(r.Fields, f)
+		}
+		return nil
+	})
+	return r, err
+}
+
+// walkFields calls fn for each field in the message, with the value for varint fields or the bytes for
+// length-delimited fields. Unknown field numbers are passed through for the caller to ignore.
+func walkFields(msg []byte, fn func(num int, v uint64, b []byte) error) error {
+	for len(msg) > 0 {
+		tag, n := binary.Uvarint(msg)
+		if n <= 0 {
+			return fmt.Errorf("invalid field tag")
+		}
+		msg = msg[n:]
+
+		num := int(tag >> 3)
+		switch tag & 0x7 {
+		case wireVarint:
+			v, n := binary.Uvarint(msg)
+			if n <= 0 {
+				return fmt.Errorf("invalid varint for field %d", num)
+			}
+			msg = msg[n:]
+			if err := fn(num, v, nil); err != nil {
+				return err
+			}
+		case wireBytes:
+			l, n := binary.Uvarint(msg)
+			if n <= 0 || l > uint64(len(msg)-n) {
+				return fmt.Errorf("invalid length for field %d", num)
+			}
+			b := msg[n : n+int(l)]
+			msg = msg[n+int(l):]
+			if err := fn(num, 0, b); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported wire type %d for field %d", tag&0x7, num)
+		}
+	}
+	return nil
+}
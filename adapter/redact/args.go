@@ -0,0 +1,53 @@
+package redact
+
+import (
+	"strings"
+)
+
+// Args returns a copy of the given command-line arguments that is safe to log, where the values of any of the given
+// sensitive flags are masked. Both "--flag=value" and "--flag value" forms are handled (as well as single-dash
+// flags), and sensitive flags may be given with or without leading dashes (e.g. "token" or "--token"). Arguments
+// after a "--" terminator are treated as positional and left as-is.
+func Args(args []string, sensitiveFlags []string) []string {
+	sensitive := make(map[string]struct{}, len(sensitiveFlags))
+	for _, f := range sensitiveFlags {
+		if name := strings.TrimLeft(f, "-"); name != "" {
+			sensitive[name] = struct{}{}
+		}
+	}
+
+	result := make([]string, len(args))
+	copy(result, args)
+
+	for i := 0; i < len(result); i++ {
+		arg := result[i]
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+
+		name := strings.TrimLeft(arg, "-")
+		value := ""
+		hasValue := false
+		if idx := strings.IndexByte(name, '='); idx >= 0 {
+			name, value, hasValue = name[:idx], name[idx+1:], true
+		}
+		if _, ok := sensitive[name]; !ok {
+			continue
+		}
+
+		switch {
+		case hasValue:
+			if value != "" {
+				result[i] = arg[:len(arg)-len(value)] + redactionMarker
+			}
+		case i+1 < len(result):
+			// the value is the next argument
+			i++
+			result[i] = redactionMarker
+		}
+	}
+	return result
+}
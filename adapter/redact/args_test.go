@@ -0,0 +1,60 @@
+package redact
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		sensitive []string
+		want      []string
+	}{
+		{
+			name:      "mixed flag styles",
+			args:      []string{"app", "--token=abc", "--password", "hunter2", "--user", "bob", "-k", "secret", "--verbose"},
+			sensitive: []string{"token", "--password", "-k"},
+			want:      []string{"app", "--token=*******", "--password", "*******", "--user", "bob", "-k", "*******", "--verbose"},
+		},
+		{
+			name:      "empty value",
+			args:      []string{"--token=", "x"},
+			sensitive: []string{"token"},
+			want:      []string{"--token=", "x"},
+		},
+		{
+			name:      "sensitive flag as the last argument",
+			args:      []string{"app", "--token"},
+			sensitive: []string{"token"},
+			want:      []string{"app", "--token"},
+		},
+		{
+			name:      "value containing an equals sign",
+			args:      []string{"--token=a=b"},
+			sensitive: []string{"token"},
+			want:      []string{"--token=*******"},
+		},
+		{
+			name:      "similar flag names are not masked",
+			args:      []string{"--token-file", "/path", "--tokens=3"},
+			sensitive: []string{"token"},
+			want:      []string{"--token-file", "/path", "--tokens=3"},
+		},
+		{
+			name:      "arguments after the terminator are positional",
+			args:      []string{"--token", "abc", "--", "--token", "literal"},
+			sensitive: []string{"token"},
+			want:      []string{"--token", "*******", "--", "--token", "literal"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := append([]string{}, tt.args...)
+			assert.Equal(t, tt.want, Args(tt.args, tt.sensitive))
+			assert.Equal(t, original, tt.args, "input should not be modified")
+		})
+	}
+}
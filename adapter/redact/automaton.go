@@ -0,0 +1,152 @@
+package redact
+
+import (
+	"sort"
+)
+
+var _ matcher = (*automaton)(nil)
+
+// automaton is an Aho-Corasick automaton over the bytes of a set of values, finding all occurrences of every value in
+// a single pass over the input (regardless of how many values there are).
+type automaton struct {
+	nodes []acNode
+}
+
+type acNode struct {
+	// edges are the trie transitions out of this node, sorted by byte
+	edges []acEdge
+	// fail is the node for the longest proper suffix of this node's path that is also a path in the trie
+	fail int32
+	// output is the nearest node along the fail chain (including this node) that completes a value, or -1
+	output int32
+	// depth is the length of the path to this node (and so the length of the value this node completes, if any)
+	depth int32
+	// terminal is true when the path to this node is a complete value
+	terminal bool
+}
+
+type acEdge struct {
+	b    byte
+	next int32
+}
+
+func newAutomaton(values [][]byte) *automaton {
+	a := &automaton{nodes: []acNode{{output: -1}}}
+	for _, v := range values {
+		a.insert(v)
+	}
+	a.link()
+	return a
+}
+
+func (a *automaton) insert(value []byte) {
+	if len(value) == 0 {
+		return
+	}
+	var cur int32
+	for i := 0; i < len(value); i++ {
+		next, ok := a.child(cur, value[i])
+		if !ok {
+			next = int32(len(a.nodes))
+			a.nodes = append(a.nodes, acNode{output: -1, depth: a.nodes[cur].depth + 1})
+			a.addEdge(cur, value[i], next)
+		}
+		cur = next
+	}
+	a.nodes[cur].terminal = true
+}
+
+func (a *automaton) addEdge(node int32, b byte, next int32) {
+	edges := a.nodes[node].edges
+	idx := sort.Search(len(edges), func(i int) bool { return edges[i].b >= b })
+	edges = append(edges, acEdge{})
+	copy(edges[idx+1:], edges[idx:])
+	edges[idx] = acEdge{b: b, next: next}
+	a.nodes[node].edges = edges
+}
+
+func (a *automaton) child(node int32, b byte) (int32, bool) {
+	edges := a.nodes[node].edges
+	// most nodes have very few edges, where a linear scan is faster than a binary search
+	if len(edges) <= 8 {
+		for _, e := range edges {
+			if e.b == b {
+				return e.next, true
+			}
+		}
+		return 0, false
+	}
+	idx := sort.Search(len(edges), func(i int) bool { return edges[i].b >= b })
+	if idx < len(edges) && edges[idx].b == b {
+		return edges[idx].next, true
+	}
+	return 0, false
+}
+
+// link computes the fail and output links with a breadth-first traversal of the trie
+func (a *automaton) link() {
+	queue := make([]int32, 0, len(a.nodes))
+	for _, e := range a.nodes[0].edges {
+		a.nodes[e.next].fail = 0
+		a.setOutput(e.next)
+		queue = append(queue, e.next)
+	}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for _, e := range a.nodes[cur].edges {
+			f := a.nodes[cur].fail
+			for {
+				if next, ok := a.child(f, e.b); ok {
+					a.nodes[e.next].fail = next
+					break
+				}
+				if f == 0 {
+					a.nodes[e.next].fail = 0
+					break
+				}
+				f = a.nodes[f].fail
+			}
+			a.setOutput(e.next)
+			queue = append(queue, e.next)
+		}
+	}
+}
+
+func (a *automaton) setOutput(node int32) {
+	if a.nodes[node].terminal {
+		a.nodes[node].output = node
+		return
+	}
+	a.nodes[node].output = a.nodes[a.nodes[node].fail].output
+}
+
+// step returns the state after consuming the given byte from the given state
+func (a *automaton) step(state int32, b byte) int32 {
+	for {
+		if next, ok := a.child(state, b); ok {
+			return next
+		}
+		if state == 0 {
+			return 0
+		}
+		state = a.nodes[state].fail
+	}
+}
+
+// occurrences returns the span of every occurrence of every value in s (including occurrences that overlap)
+func (a *automaton) occurrences(s string) []span {
+	if len(a.nodes) == 1 {
+		return nil
+	}
+
+	var spans []span
+	var state int32
+	for i := 0; i < len(s); i++ {
+		state = a.step(state, s[i])
+		for o := a.nodes[state].output; o >= 0; o = a.nodes[a.nodes[o].fail].output {
+			spans = append(spans, span{start: i + 1 - int(a.nodes[o].depth), end: i + 1})
+		}
+	}
+	return spans
+}
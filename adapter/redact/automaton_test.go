@@ -0,0 +1,202 @@
+package redact
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func toBytes(values []string) [][]byte {
+	var b [][]byte
+	for _, v := range values {
+		b = append(b, []byte(v))
+	}
+	return b
+}
+
+// replaceReference is the replacement that the automaton must be equivalent to: every occurrence of every value is
+// found with strings.Index, and overlapping occurrences are replaced together
+func replaceReference(values []string, input string) string {
+	// covered marks bytes within any occurrence, and joined marks bytes within the same occurrence as the byte before
+	covered := make([]bool, len(input))
+	joined := make([]bool, len(input))
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+		for offset := 0; ; offset++ {
+			idx := strings.Index(input[offset:], v)
+			if idx < 0 {
+				break
+			}
+			offset += idx
+			for i := offset; i < offset+len(v); i++ {
+				covered[i] = true
+				joined[i] = joined[i] || i > offset
+			}
+		}
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(input); i++ {
+		if !covered[i] {
+			sb.WriteByte(input[i])
+			continue
+		}
+		if !joined[i] {
+			sb.WriteString(redactionMarker)
+		}
+	}
+	return sb.String()
+}
+
+func Test_automaton_Replace(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		input  string
+		want   string
+	}{
+		{
+			name:   "no values",
+			values: nil,
+			input:  "nothing to see here",
+			want:   "nothing to see here",
+		},
+		{
+			name:   "no occurrences",
+			values: []string{"secret"},
+			input:  "nothing to see here",
+			want:   "nothing to see here",
+		},
+		{
+			name:   "value within a failed partial match of another value",
+			values: []string{"abcx", "bcd"},
+			input:  "abcd",
+			want:   "a*******",
+		},
+		{
+			name:   "overlapping occurrences are replaced together",
+			values: []string{"abc", "bcdefg"},
+			input:  "abcdefg",
+			want:   "*******",
+		},
+		{
+			name:   "chain of overlapping occurrences",
+			values: []string{"abc", "cde", "efg"},
+			input:  "xabcdefgx",
+			want:   "x*******x",
+		},
+		{
+			name:   "overlapping occurrences of the same value",
+			values: []string{"aba"},
+			input:  "ababa",
+			want:   "*******",
+		},
+		{
+			name:   "adjacent occurrences",
+			values: []string{"ab", "cd"},
+			input:  "abcdab",
+			want:   "*********************",
+		},
+		{
+			name:   "unicode values",
+			values: []string{"秘密", "秘密の鍵"},
+			input:  "これは秘密の鍵と秘密です",
+			want:   "これは*******と*******です",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := replaceOccurrences(tt.input, newAutomaton(toBytes(tt.values)).occurrences(tt.input), fixedMarker)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.want, replaceReference(tt.values, tt.input))
+		})
+	}
+}
+
+func Test_automaton_MatchesReplacer(t *testing.T) {
+	// a small alphabet produces many overlapping values and occurrences
+	alphabet := []rune("ab秘c")
+	random := rand.New(rand.NewSource(1))
+	randomString := func(maxLen int) string {
+		var sb strings.Builder
+		for n := 1 + random.Intn(maxLen); n > 0; n-- {
+			sb.WriteRune(alphabet[random.Intn(len(alphabet))])
+		}
+		return sb.String()
+	}
+
+	for i := 0; i < 500; i++ {
+		var values []string
+		for n := random.Intn(8); n > 0; n-- {
+			values = append(values, randomString(5))
+		}
+		input := randomString(40)
+
+		got, _ := replaceOccurrences(input, newAutomaton(toBytes(values)).occurrences(input), fixedMarker)
+		assert.Equal(t, replaceReference(values, input), got, "values=%q input=%q", values, input)
+	}
+}
+
+func Test_store_RedactString_ConcurrentAdd(t *testing.T) {
+	s := NewStore("secret-0")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				s.Add(fmt.Sprintf("secret-%d-%d", i, j))
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				assert.Equal(t, "a ******* b", s.RedactString("a secret-0 b"))
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, "*******", s.RedactString("secret-7-49"))
+}
+
+// Benchmark_store_RedactString compares a single pass over the input with one replacement pass per value
+func Benchmark_store_RedactString(b *testing.B) {
+	var values []string
+	for i := 0; i < 5000; i++ {
+		values = append(values, fmt.Sprintf("secret-%04d-%x", i, i*7919))
+	}
+	line := strings.Repeat("some log line that mentions "+values[42]+" and "+values[4999]+" along the way. ", 10)
+
+	b.Run("replace-per-value", func(b *testing.B) {
+		sorted := append([]string(nil), values...)
+		sort.Slice(sorted, func(i, j int) bool {
+			return len(sorted[i]) > len(sorted[j])
+		})
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			s := line
+			for _, v := range sorted {
+				s = strings.ReplaceAll(s, v, redactionMarker)
+			}
+		}
+	})
+
+	b.Run("automaton", func(b *testing.B) {
+		s := NewStore(values...)
+		// build the matcher outside of the timed loop
+		s.RedactString(line)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			s.RedactString(line)
+		}
+	})
+}
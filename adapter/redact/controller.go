@@ -0,0 +1,133 @@
+package redact
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	iface "github.com/anchore/go-logger"
+)
+
+var _ Controller = (*redactingController)(nil)
+
+// Controller is an iface.Controller that can wrap the current output with a redacting writer at runtime
+type Controller interface {
+	iface.Controller
+	// EnableRedaction wraps the current output such that all content is redacted with the given redactor. Calling
+	// this while redaction is already enabled adds the given redactor to the existing set. See NewRedactingWriter for
+	// which redactors catch secrets split across writes.
+	EnableRedaction(r Redactor) error
+	// DisableRedaction restores the unwrapped output, flushing any content held back by the redacting writer.
+	DisableRedaction() error
+}
+
+type redactingController struct {
+	controller iface.Controller
+	original   io.Writer
+	redactor   Redactor
+	writer     io.WriteCloser
+	// output is installed as the output of the controller while redaction is enabled, so that the redacting writer can
+	// be swapped while writes are blocked
+	output *switchingWriter
+	lock   sync.Mutex
+}
+
+// switchingWriter writes to a target that can be swapped, blocking writes while the target is being swapped
+type switchingWriter struct {
+	target io.Writer
+	lock   sync.Mutex
+}
+
+func (s *switchingWriter) Write(p []byte) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.target.Write(p)
+}
+
+// swap flushes any content held back by the previous writer before any content is written to the given target
+func (s *switchingWriter) swap(previous io.Closer, target io.Writer) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var err error
+	if previous != nil {
+		err = previous.Close()
+	}
+	s.target = target
+	return err
+}
+
+// NewController wraps the given controller such that redaction of the output can be enabled and disabled at runtime.
+func NewController(c iface.Controller) Controller {
+	return &redactingController{
+		controller: c,
+	}
+}
+
+func (c *redactingController) EnableRedaction(r Redactor) error {
+	if r == nil {
+		return fmt.Errorf("no redactor provided")
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	previous := c.writer
+	if previous == nil {
+		c.original = c.controller.GetOutput()
+		if c.original == nil {
+			return fmt.Errorf("no output configured to redact")
+		}
+		c.redactor = r
+	} else {
+		c.redactor = newRedactorCollection(c.redactor, r)
+	}
+
+	// the new writer is installed in a single step, so there is no point where content is written unredacted
+	c.writer = NewRedactingWriter(c.original, c.redactor)
+	if previous == nil {
+		c.output = &switchingWriter{target: c.writer}
+		c.controller.SetOutput(c.output)
+		return nil
+	}
+	return c.output.swap(previous, c.writer)
+}
+
+func (c *redactingController) DisableRedaction() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.writer == nil {
+		return nil
+	}
+
+	// held back content is flushed before anything else is written to the original output, which is then restored
+	err := c.output.swap(c.writer, c.original)
+	c.controller.SetOutput(c.original)
+
+	c.writer = nil
+	c.redactor = nil
+	c.original = nil
+	c.output = nil
+	return err
+}
+
+// SetOutput sets the output of the wrapped controller, keeping redaction in place if it is enabled.
+func (c *redactingController) SetOutput(w io.Writer) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.writer == nil {
+		c.controller.SetOutput(w)
+		return
+	}
+
+	previous := c.writer
+	c.original = w
+	c.writer = NewRedactingWriter(w, c.redactor)
+	_ = c.output.swap(previous, c.writer)
+}
+
+func (c *redactingController) GetOutput() io.Writer {
+	return c.controller.GetOutput()
+}
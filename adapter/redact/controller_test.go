@@ -0,0 +1,193 @@
+package redact
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/go-logger"
+	"github.com/anchore/go-logger/adapter/logrus"
+)
+
+// syncBuffer is a bytes.Buffer that is safe for concurrent use
+type syncBuffer struct {
+	buf  bytes.Buffer
+	lock sync.Mutex
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	return b.buf.String()
+}
+
+func Test_redactingController_EnableDisable(t *testing.T) {
+	out, err := logrus.New(logrus.Config{Level: logger.InfoLevel})
+	require.NoError(t, err)
+
+	buff := &syncBuffer{}
+	out.(logger.Controller).SetOutput(buff)
+
+	c := NewController(out.(logger.Controller))
+
+	out.Info("before: hunter2")
+	require.NoError(t, c.EnableRedaction(NewStore("hunter2")))
+	out.Info("during: hunter2")
+	require.NoError(t, c.DisableRedaction())
+	out.Info("after: hunter2")
+
+	assert.Same(t, buff, c.GetOutput())
+
+	result := buff.String()
+	assert.Contains(t, result, "before: hunter2")
+	assert.Contains(t, result, "during: *******")
+	assert.NotContains(t, result, "during: hunter2")
+	assert.Contains(t, result, "after: hunter2")
+}
+
+func Test_redactingController_ConcurrentToggle(t *testing.T) {
+	out, err := logrus.New(logrus.Config{Level: logger.InfoLevel})
+	require.NoError(t, err)
+
+	buff := &syncBuffer{}
+	out.(logger.Controller).SetOutput(buff)
+
+	c := NewController(out.(logger.Controller))
+
+	// loggers hold a read lock while checking whether redaction is enabled and logging, and redaction is only disabled
+	// under the write lock, so any message tagged "guarded" was logged strictly while redaction was enabled.
+	var stateLock sync.RWMutex
+	enabled := false
+
+	wg := sync.WaitGroup{}
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				stateLock.RLock()
+				tag := "open"
+				if enabled {
+					tag = "guarded"
+				}
+				out.Infof("goroutine=%d i=%d tag=%s value=hunter2", g, i, tag)
+				stateLock.RUnlock()
+			}
+		}(g)
+	}
+
+	for i := 0; i < 50; i++ {
+		require.NoError(t, c.EnableRedaction(NewStore("hunter2")))
+		stateLock.Lock()
+		enabled = true
+		stateLock.Unlock()
+
+		stateLock.Lock()
+		enabled = false
+		require.NoError(t, c.DisableRedaction())
+		stateLock.Unlock()
+	}
+
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSpace(buff.String()), "\n")
+	require.Len(t, lines, 800)
+	for _, line := range lines {
+		if strings.Contains(line, "tag=guarded") {
+			assert.NotContains(t, line, "hunter2", fmt.Sprintf("raw secret found in line: %q", line))
+		}
+	}
+}
+
+func Test_redactingController_SwapKeepsLinesIntact(t *testing.T) {
+	out, err := logrus.New(logrus.Config{Level: logger.InfoLevel})
+	require.NoError(t, err)
+
+	buff := &syncBuffer{}
+	out.(logger.Controller).SetOutput(buff)
+
+	c := NewController(out.(logger.Controller))
+
+	// logging is not coordinated with enabling or disabling redaction in any way
+	wg := sync.WaitGroup{}
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				out.Infof("goroutine=%d i=%d value=hunter2 padding=%s", g, i, strings.Repeat("x", 40))
+			}
+		}(g)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	for toggling := true; toggling; {
+		select {
+		case <-done:
+			toggling = false
+		default:
+			require.NoError(t, c.EnableRedaction(NewStore("hunter2")))
+			require.NoError(t, c.EnableRedaction(NewStore("other")))
+			c.SetOutput(buff)
+			require.NoError(t, c.DisableRedaction())
+		}
+	}
+
+	// held back content of a partially flushed line is never written after (or within) other lines
+	line := regexp.MustCompile(`^\[\d+\] +INFO goroutine=\d+ i=\d+ value=(hunter2|\*{7}) padding=x{40}$`)
+	lines := strings.Split(strings.TrimSpace(buff.String()), "\n")
+	require.Len(t, lines, 800)
+	for _, l := range lines {
+		assert.Regexp(t, line, l)
+	}
+}
+
+// interleavingController logs a message whenever the output is set, as another goroutine may do at any time
+type interleavingController struct {
+	logger.Controller
+	log logger.Logger
+}
+
+func (c *interleavingController) SetOutput(w io.Writer) {
+	c.Controller.SetOutput(w)
+	c.log.Info("logged while the output was being set")
+}
+
+func Test_redactingController_HeldBackContentWrittenFirst(t *testing.T) {
+	out, err := logrus.New(logrus.Config{Level: logger.InfoLevel})
+	require.NoError(t, err)
+
+	buff := &syncBuffer{}
+	out.(logger.Controller).SetOutput(buff)
+
+	c := NewController(&interleavingController{Controller: out.(logger.Controller), log: out})
+	require.NoError(t, c.EnableRedaction(NewStore("hunter2")))
+
+	// the end of this line is held back by the redacting writer
+	out.Infof("value=hunter2 padding=%s", strings.Repeat("x", 80))
+	require.NoError(t, c.DisableRedaction())
+
+	lines := strings.Split(strings.TrimSpace(buff.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], "logged while the output was being set")
+	assert.Contains(t, lines[1], "value=******* padding="+strings.Repeat("x", 80))
+	assert.Contains(t, lines[2], "logged while the output was being set")
+}
@@ -0,0 +1,138 @@
+package redact
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var _ Redactor = (*delimitedRedactor)(nil)
+var _ CutAdjuster = (*delimitedRedactor)(nil)
+
+// delimitedRedactor masks any content found between an opening and closing delimiter (e.g. <<SECRET>>...<</SECRET>>)
+type delimitedRedactor struct {
+	open             string
+	close            string
+	redactDelimiters bool
+	_id              string
+}
+
+type DelimitedRedactorOption func(*delimitedRedactor)
+
+// WithRedactedDelimiters masks the delimiters themselves along with the content between them.
+func WithRedactedDelimiters() DelimitedRedactorOption {
+	return func(r *delimitedRedactor) {
+		r.redactDelimiters = true
+	}
+}
+
+// NewDelimitedRedactor returns a Redactor that masks everything between the given open and close delimiters. Nested
+// delimiters are masked as a single span (from the outermost open to its matching close), and an open delimiter
+// without a matching close masks the remainder of the string, since the secret cannot be proven to have ended.
+// A close delimiter without a preceding open delimiter is left as-is. When used with a redacting writer, content from
+// an open delimiter onwards is held back until its matching close has been written (or until Close).
+func NewDelimitedRedactor(open, close string, opts ...DelimitedRedactorOption) Redactor {
+	r := &delimitedRedactor{
+		open:  open,
+		close: close,
+		_id:   uuid.New().String(),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+func (r *delimitedRedactor) id() string {
+	return r._id
+}
+
+func (r *delimitedRedactor) RedactString(str string) string {
+	if r.open == "" || r.close == "" {
+		return str
+	}
+
+	var sb strings.Builder
+	for {
+		start := strings.Index(str, r.open)
+		if start < 0 {
+			sb.WriteString(str)
+			break
+		}
+		sb.WriteString(str[:start])
+
+		end := r.matchingClose(str, start+len(r.open))
+		if end < 0 {
+			// unbalanced: mask everything that remains
+			r.writeMasked(&sb, false)
+			break
+		}
+
+		r.writeMasked(&sb, true)
+		str = str[end:]
+	}
+	return sb.String()
+}
+
+// AdjustCut holds back everything from an open delimiter whose matching close has not been written before the cut,
+// including an open delimiter that has only been partially written so far
+func (r *delimitedRedactor) AdjustCut(content string, cut int) int {
+	if r.open == "" || r.close == "" {
+		return cut
+	}
+
+	offset := 0
+	for {
+		start := strings.Index(content[offset:], r.open)
+		if start < 0 {
+			break
+		}
+		start += offset
+		if start >= cut {
+			break
+		}
+		end := r.matchingClose(content, start+len(r.open))
+		if end < 0 || end > cut {
+			return start
+		}
+		offset = end
+	}
+
+	if start := pendingIndex(content, r.open, cut, false, false); start >= 0 {
+		return start
+	}
+	return cut
+}
+
+// matchingClose returns the index just past the close delimiter that balances the open delimiter ending at the given
+// offset, or -1 if there is no such close delimiter.
+func (r *delimitedRedactor) matchingClose(str string, offset int) int {
+	depth := 1
+	for depth > 0 {
+		nextClose := strings.Index(str[offset:], r.close)
+		if nextClose < 0 {
+			return -1
+		}
+		nextOpen := strings.Index(str[offset:], r.open)
+		if nextOpen >= 0 && nextOpen < nextClose {
+			depth++
+			offset += nextOpen + len(r.open)
+			continue
+		}
+		depth--
+		offset += nextClose + len(r.close)
+	}
+	return offset
+}
+
+func (r *delimitedRedactor) writeMasked(sb *strings.Builder, closed bool) {
+	if r.redactDelimiters {
+		sb.WriteString(redactionMarker)
+		return
+	}
+	sb.WriteString(r.open)
+	sb.WriteString(redactionMarker)
+	if closed {
+		sb.WriteString(r.close)
+	}
+}
@@ -0,0 +1,149 @@
+package redact
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_delimitedRedactor_RedactString(t *testing.T) {
+	tests := []struct {
+		name             string
+		open             string
+		close            string
+		redactDelimiters bool
+		input            string
+		want             string
+	}{
+		{
+			name:  "balanced",
+			open:  "<<SECRET>>",
+			close: "<</SECRET>>",
+			input: "user=bob pass=<<SECRET>>hunter2<</SECRET>> done",
+			want:  "user=bob pass=<<SECRET>>*******<</SECRET>> done",
+		},
+		{
+			name:             "balanced with delimiters redacted",
+			open:             "<<SECRET>>",
+			close:            "<</SECRET>>",
+			redactDelimiters: true,
+			input:            "user=bob pass=<<SECRET>>hunter2<</SECRET>> done",
+			want:             "user=bob pass=******* done",
+		},
+		{
+			name:  "multiple spans",
+			open:  "[",
+			close: "]",
+			input: "a [one] b [two] c",
+			want:  "a [*******] b [*******] c",
+		},
+		{
+			name:  "nested spans are masked as one",
+			open:  "[",
+			close: "]",
+			input: "a [one [two] three] b",
+			want:  "a [*******] b",
+		},
+		{
+			name:  "unclosed open masks the remainder",
+			open:  "<<SECRET>>",
+			close: "<</SECRET>>",
+			input: "pass=<<SECRET>>hunter2 and more",
+			want:  "pass=<<SECRET>>*******",
+		},
+		{
+			name:  "unbalanced nesting masks the remainder",
+			open:  "[",
+			close: "]",
+			input: "a [one [two] three",
+			want:  "a [*******",
+		},
+		{
+			name:  "close without open is untouched",
+			open:  "[",
+			close: "]",
+			input: "a one] b",
+			want:  "a one] b",
+		},
+		{
+			name:  "identical open and close delimiters",
+			open:  "|",
+			close: "|",
+			input: "a |one| b |two| c",
+			want:  "a |*******| b |*******| c",
+		},
+		{
+			name:  "empty delimiter is a no-op",
+			open:  "",
+			close: "]",
+			input: "a one] b",
+			want:  "a one] b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []DelimitedRedactorOption
+			if tt.redactDelimiters {
+				opts = append(opts, WithRedactedDelimiters())
+			}
+			r := NewDelimitedRedactor(tt.open, tt.close, opts...)
+			assert.Equal(t, tt.want, r.RedactString(tt.input))
+		})
+	}
+}
+
+func Test_delimitedRedactor_Writer(t *testing.T) {
+	secret := strings.Repeat("s3cr3t ", 40)
+
+	tests := []struct {
+		name     string
+		redactor Redactor
+		input    string
+		want     string
+	}{
+		{
+			name:     "secret longer than the window",
+			redactor: NewDelimitedRedactor("<<SECRET>>", "<</SECRET>>"),
+			input:    "before <<SECRET>>" + secret + "<</SECRET>> after\n",
+			want:     "before <<SECRET>>*******<</SECRET>> after\n",
+		},
+		{
+			name:     "nested delimiters",
+			redactor: NewDelimitedRedactor("<<SECRET>>", "<</SECRET>>", WithRedactedDelimiters()),
+			input:    "before <<SECRET>>" + secret + "<<SECRET>>inner<</SECRET>>" + secret + "<</SECRET>> after\n",
+			want:     "before ******* after\n",
+		},
+		{
+			name:     "unclosed delimiter",
+			redactor: NewDelimitedRedactor("<<SECRET>>", "<</SECRET>>"),
+			input:    "before <<SECRET>>" + secret,
+			want:     "before <<SECRET>>*******",
+		},
+		{
+			name:     "delimiters longer than half of the window",
+			redactor: NewDelimitedRedactor("<<"+strings.Repeat("SECRET", 10)+">>", "<</SECRET>>"),
+			input:    "before <<" + strings.Repeat("SECRET", 10) + ">>" + secret + "<</SECRET>> after\n",
+			want:     "before <<" + strings.Repeat("SECRET", 10) + ">>*******<</SECRET>> after\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// wherever the delimiters fall relative to the window and the writes, the secret must be masked whole
+			for pad := 0; pad < 80; pad++ {
+				for _, chunkSize := range []int{1, 10, 100} {
+					padding := strings.Repeat("p", pad) + "\n"
+
+					out := &bytes.Buffer{}
+					w := NewRedactingWriter(out, tt.redactor)
+					writeChunked(t, w, padding+tt.input, chunkSize)
+					require.NoError(t, w.Close())
+
+					require.Equal(t, padding+tt.want, out.String(), "pad=%d chunkSize=%d", pad, chunkSize)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package redact
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+var _ Redactor = (*keyIndicatorRedactor)(nil)
+var _ CutAdjuster = (*keyIndicatorRedactor)(nil)
+
+// keyIndicatorRedactor masks values that follow a key indicator (e.g. "password:" or "token="), leaving other
+// occurrences of the same value untouched
+type keyIndicatorRedactor struct {
+	indicators []string
+	pattern    *regexp.Regexp
+	delimiters string
+	_id        string
+}
+
+// NewKeyIndicatorRedactor returns a Redactor that masks the value immediately following any of the given key
+// indicators (matched case-insensitively). Spaces and tabs directly after the indicator are skipped, and the value
+// extends up to the next whitespace or any of the characters in delimiters (e.g. ",;&"). When used with a redacting
+// writer, content from an indicator onwards is held back until its value has been ended.
+func NewKeyIndicatorRedactor(indicators []string, delimiters string) Redactor {
+	r := &keyIndicatorRedactor{
+		delimiters: delimiters,
+		_id:        uuid.New().String(),
+	}
+	var quoted []string
+	for _, i := range indicators {
+		if i == "" {
+			continue
+		}
+		r.indicators = append(r.indicators, i)
+		quoted = append(quoted, regexp.QuoteMeta(i))
+	}
+	if len(quoted) > 0 {
+		r.pattern = regexp.MustCompile(`(?i)(?:` + strings.Join(quoted, "|") + `)[ \t]*`)
+	}
+	return r
+}
+
+func (r *keyIndicatorRedactor) id() string {
+	return r._id
+}
+
+func (r *keyIndicatorRedactor) RedactString(str string) string {
+	if r.pattern == nil {
+		return str
+	}
+
+	var spans [][]int
+	for _, m := range r.pattern.FindAllStringIndex(str, -1) {
+		start := m[1]
+		end := start + r.valueLength(str[start:])
+		if end > start {
+			spans = append(spans, []int{start, end})
+		}
+	}
+	if len(spans) == 0 {
+		return str
+	}
+
+	return replaceSpans(str, spans, redactionMarker)
+}
+
+// AdjustCut holds back everything from an indicator whose value has not been ended before the cut, including an
+// indicator that has only been partially written so far
+func (r *keyIndicatorRedactor) AdjustCut(content string, cut int) int {
+	if r.pattern == nil {
+		return cut
+	}
+
+	for _, m := range r.pattern.FindAllStringIndex(content, -1) {
+		if m[0] >= cut {
+			break
+		}
+		// the value (or the space before it) may continue past the cut
+		if m[1]+r.valueLength(content[m[1]:]) >= cut {
+			return m[0]
+		}
+	}
+
+	for _, i := range r.indicators {
+		if start := pendingIndex(content, i, cut, true, false); start >= 0 {
+			cut = start
+		}
+	}
+	return cut
+}
+
+// valueLength returns the number of bytes in the value at the start of the given string
+func (r *keyIndicatorRedactor) valueLength(str string) int {
+	for i, c := range str {
+		if unicode.IsSpace(c) || strings.ContainsRune(r.delimiters, c) {
+			return i
+		}
+	}
+	return len(str)
+}
@@ -0,0 +1,124 @@
+package redact
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_keyIndicatorRedactor_RedactString(t *testing.T) {
+	tests := []struct {
+		name       string
+		indicators []string
+		delimiters string
+		input      string
+		want       string
+	}{
+		{
+			name:       "value after indicator is masked but bare value is kept",
+			indicators: []string{"password:"},
+			input:      "password: hunter2 (hint: hunter2 is a bad password)",
+			want:       "password: ******* (hint: hunter2 is a bad password)",
+		},
+		{
+			name:       "multiple indicators",
+			indicators: []string{"password:", "token="},
+			input:      "user=bob token=abc123 password:hunter2",
+			want:       "user=bob token=******* password:*******",
+		},
+		{
+			name:       "indicators are case insensitive",
+			indicators: []string{"token="},
+			input:      "TOKEN=abc123 Token=def456",
+			want:       "TOKEN=******* Token=*******",
+		},
+		{
+			name:       "delimiters end the value",
+			indicators: []string{"token="},
+			delimiters: "&;",
+			input:      "https://host/path?token=abc123&page=2;token=def",
+			want:       "https://host/path?token=*******&page=2;token=*******",
+		},
+		{
+			name:       "value ends at a newline",
+			indicators: []string{"password:"},
+			input:      "password:\thunter2\nnext line",
+			want:       "password:\t*******\nnext line",
+		},
+		{
+			name:       "indicator without a value",
+			indicators: []string{"password:"},
+			input:      "password: \nhunter2",
+			want:       "password: \nhunter2",
+		},
+		{
+			name:       "no indicators",
+			indicators: []string{""},
+			input:      "password: hunter2",
+			want:       "password: hunter2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewKeyIndicatorRedactor(tt.indicators, tt.delimiters)
+			assert.Equal(t, tt.want, r.RedactString(tt.input))
+		})
+	}
+}
+
+func Test_keyIndicatorRedactor_Writer(t *testing.T) {
+	longIndicator := strings.Repeat("secret_", 10) + "value="
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "value straddling the cut",
+			input: "user=alice password=hunter2 " + strings.Repeat("x", 100) + "\n",
+			want:  "user=alice password=******* " + strings.Repeat("x", 100) + "\n",
+		},
+		{
+			name:  "value longer than the window",
+			input: "token: " + strings.Repeat("t0k3n", 40) + "&next\n",
+			want:  "token: *******&next\n",
+		},
+		{
+			name:  "spaces before the value longer than the window",
+			input: "token:" + strings.Repeat(" ", 100) + "t0k3n\n",
+			want:  "token:" + strings.Repeat(" ", 100) + "*******\n",
+		},
+		{
+			name:  "indicator longer than half of the window",
+			input: strings.ToUpper(longIndicator) + "hunter2\n",
+			want:  strings.ToUpper(longIndicator) + "*******\n",
+		},
+		{
+			name:  "value never ended",
+			input: "password=" + strings.Repeat("hunter2", 20),
+			want:  "password=*******",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// wherever the indicator and value fall relative to the window and the writes, the value must be masked whole
+			for pad := 0; pad < 80; pad++ {
+				for _, chunkSize := range []int{1, 7, 40, 70} {
+					padding := strings.Repeat("p", pad) + "\n"
+					r := NewKeyIndicatorRedactor([]string{"password=", "token:", longIndicator}, "&")
+
+					out := &bytes.Buffer{}
+					w := NewRedactingWriter(out, r)
+					writeChunked(t, w, padding+tt.input, chunkSize)
+					require.NoError(t, w.Close())
+
+					require.Equal(t, padding+tt.want, out.String(), "pad=%d chunkSize=%d", pad, chunkSize)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package redact
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var _ Redactor = (*linePrefixRedactor)(nil)
+
+// linePrefixRedactor masks the remainder of lines that start with a given prefix (e.g. stack frames in a panic dump)
+type linePrefixRedactor struct {
+	prefixes []*regexp.Regexp
+	_id      string
+}
+
+// NewLinePrefixRedactor returns a Redactor that, for every line that begins with a match of any of the given patterns,
+// masks the remainder of the line following the match. If a pattern has a capture group then only the text matched by
+// the first group is masked instead, leaving the rest of the line intact (e.g. `^\s*main\.login\((.*)\)$` masks only
+// the arguments of a stack frame). Note that when used with a redacting writer, lines longer than half of the writer's
+// window may be split before the prefix has been seen (see WithMinWindowSize).
+func NewLinePrefixRedactor(prefixes ...*regexp.Regexp) Redactor {
+	var ps []*regexp.Regexp
+	for _, p := range prefixes {
+		if p != nil {
+			ps = append(ps, p)
+		}
+	}
+	return &linePrefixRedactor{
+		prefixes: ps,
+		_id:      uuid.New().String(),
+	}
+}
+
+func (r *linePrefixRedactor) id() string {
+	return r._id
+}
+
+func (r *linePrefixRedactor) RedactString(str string) string {
+	if len(r.prefixes) == 0 {
+		return str
+	}
+
+	var spans [][]int
+	offset := 0
+	for _, line := range strings.SplitAfter(str, "\n") {
+		content := strings.TrimRight(line, "\r\n")
+		if start, end, ok := r.maskedSpan(content); ok {
+			spans = append(spans, []int{offset + start, offset + end})
+		}
+		offset += len(line)
+	}
+	if len(spans) == 0 {
+		return str
+	}
+
+	return replaceSpans(str, spans, redactionMarker)
+}
+
+// maskedSpan returns the portion of the given line (without line endings) to mask, if any
+func (r *linePrefixRedactor) maskedSpan(line string) (int, int, bool) {
+	for _, p := range r.prefixes {
+		m := p.FindStringSubmatchIndex(line)
+		if m == nil || m[0] != 0 {
+			continue
+		}
+		start, end := m[1], len(line)
+		if len(m) > 2 {
+			start, end = m[2], m[3]
+		}
+		if start < 0 || end <= start {
+			continue
+		}
+		return start, end, true
+	}
+	return 0, 0, false
+}
@@ -0,0 +1,77 @@
+package redact
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_linePrefixRedactor_RedactString(t *testing.T) {
+	stack := "panic: login failed\n" +
+		"\n" +
+		"goroutine 1 [running]:\n" +
+		"main.login({0xc000012345, 0x7}, {0x4b1a2c, 0x8})\n" +
+		"\t/home/user/app/main.go:12 +0x1d\n" +
+		"main.main()\n" +
+		"\t/home/user/app/main.go:20 +0x25\n" +
+		"exit status 2"
+
+	tests := []struct {
+		name     string
+		prefixes []*regexp.Regexp
+		input    string
+		want     string
+	}{
+		{
+			name:     "remainder of matching lines is masked",
+			prefixes: []*regexp.Regexp{regexp.MustCompile(`main\.login`)},
+			input:    stack,
+			want: "panic: login failed\n" +
+				"\n" +
+				"goroutine 1 [running]:\n" +
+				"main.login*******\n" +
+				"\t/home/user/app/main.go:12 +0x1d\n" +
+				"main.main()\n" +
+				"\t/home/user/app/main.go:20 +0x25\n" +
+				"exit status 2",
+		},
+		{
+			name:     "only the capture group is masked",
+			prefixes: []*regexp.Regexp{regexp.MustCompile(`^main\.\w+\((.+)\)$`)},
+			input:    stack,
+			want: "panic: login failed\n" +
+				"\n" +
+				"goroutine 1 [running]:\n" +
+				"main.login(*******)\n" +
+				"\t/home/user/app/main.go:12 +0x1d\n" +
+				"main.main()\n" +
+				"\t/home/user/app/main.go:20 +0x25\n" +
+				"exit status 2",
+		},
+		{
+			name:     "prefix must be at the start of the line",
+			prefixes: []*regexp.Regexp{regexp.MustCompile(`failed`)},
+			input:    stack,
+			want:     stack,
+		},
+		{
+			name:     "line endings are preserved",
+			prefixes: []*regexp.Regexp{regexp.MustCompile(`token: `)},
+			input:    "token: abc123\r\ntoken: \r\nother",
+			want:     "token: *******\r\ntoken: \r\nother",
+		},
+		{
+			name:     "no prefixes",
+			prefixes: nil,
+			input:    stack,
+			want:     stack,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewLinePrefixRedactor(tt.prefixes...)
+			assert.Equal(t, tt.want, r.RedactString(tt.input))
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package redact
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+var _ Redactor = (*lineScopedRedactor)(nil)
+var _ CutAdjuster = (*lineScopedRedactor)(nil)
+
+// lineScopedRedactor applies a redactor only to lines matching a predicate
+type lineScopedRedactor struct {
+	match func(line string) bool
+	inner Redactor
+	_id   string
+}
+
+// NewLineScopedRedactor returns a Redactor that applies the inner redactor only to lines for which match returns true
+// (e.g. lines containing "http"), passing all other lines through as-is. This limits the cost and false positives of
+// broad redactors. Lines are given to match without their line endings, which are preserved. When used with a
+// redacting writer, content is only flushed up to the end of the last complete line, so a line that is never ended is
+// held back until Close.
+func NewLineScopedRedactor(match func(line string) bool, inner Redactor) Redactor {
+	return &lineScopedRedactor{
+		match: match,
+		inner: inner,
+		_id:   uuid.New().String(),
+	}
+}
+
+func (r *lineScopedRedactor) id() string {
+	return r._id
+}
+
+func (r *lineScopedRedactor) RedactString(str string) string {
+	if r.match == nil || r.inner == nil {
+		return str
+	}
+
+	var sb strings.Builder
+	for _, line := range strings.SplitAfter(str, "\n") {
+		content := strings.TrimRight(line, "\r\n")
+		if !r.match(content) {
+			sb.WriteString(line)
+			continue
+		}
+		sb.WriteString(r.inner.RedactString(content))
+		sb.WriteString(line[len(content):])
+	}
+	return sb.String()
+}
+
+// AdjustCut holds back the line the cut falls within, since whether it is redacted depends on the whole line
+func (r *lineScopedRedactor) AdjustCut(content string, cut int) int {
+	if cut > 0 && content[cut-1] != '\n' {
+		cut = strings.LastIndexByte(content[:cut], '\n') + 1
+	}
+	return adjustCut(r.inner, content, cut)
+}
@@ -0,0 +1,117 @@
+package redact
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_lineScopedRedactor_RedactString(t *testing.T) {
+	containsHTTP := func(line string) bool { return strings.Contains(line, "http") }
+
+	tests := []struct {
+		name  string
+		match func(string) bool
+		input string
+		want  string
+	}{
+		{
+			name:  "only matching lines are redacted",
+			match: containsHTTP,
+			input: "GET http://host/?token=abc123\ntoken=abc123\nPOST https://host/abc123",
+			want:  "GET http://host/?token=*******\ntoken=abc123\nPOST https://host/*******",
+		},
+		{
+			name:  "line endings are preserved",
+			match: containsHTTP,
+			input: "http abc123\r\nabc123\r\n\r\nhttp abc123\n",
+			want:  "http *******\r\nabc123\r\n\r\nhttp *******\n",
+		},
+		{
+			name:  "line endings are not given to the predicate",
+			match: func(line string) bool { return strings.HasSuffix(line, "abc123") },
+			input: "a abc123\r\nb abc123\n",
+			want:  "a *******\r\nb *******\n",
+		},
+		{
+			name:  "no matching lines",
+			match: containsHTTP,
+			input: "abc123\nabc123",
+			want:  "abc123\nabc123",
+		},
+		{
+			name:  "nil predicate",
+			input: "http abc123",
+			want:  "http abc123",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewLineScopedRedactor(tt.match, NewStore("abc123"))
+			assert.Equal(t, tt.want, r.RedactString(tt.input))
+		})
+	}
+}
+
+func Test_lineScopedRedactor_Writer(t *testing.T) {
+	secret := "s3cr3t-value"
+	line := "url=" + secret + "\n"
+	other := "other=" + secret + "\n"
+
+	tests := []struct {
+		name     string
+		redactor func() Redactor
+	}{
+		{
+			name: "line scoped redactor",
+			redactor: func() Redactor {
+				return NewLineScopedRedactor(func(line string) bool { return strings.HasPrefix(line, "url=") }, NewStore(secret))
+			},
+		},
+		{
+			name: "nested within a collection",
+			redactor: func() Redactor {
+				return redactorCollection{
+					NewStore("unrelated"),
+					NewLineScopedRedactor(func(line string) bool { return strings.HasPrefix(line, "url=") }, NewStore(secret)),
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// wherever the line falls relative to the window and the writes, it must be matched whole
+			for pad := 0; pad < 80; pad++ {
+				for chunkSize := 1; chunkSize <= 32; chunkSize++ {
+					padding := strings.Repeat("p", pad) + "\n"
+					input := padding + line + other + line
+
+					out := &bytes.Buffer{}
+					w := NewRedactingWriter(out, tt.redactor())
+					writeChunked(t, w, input, chunkSize)
+					require.NoError(t, w.Close())
+
+					want := padding + "url=*******\n" + other + "url=*******\n"
+					require.Equal(t, want, out.String(), "pad=%d chunkSize=%d", pad, chunkSize)
+				}
+			}
+		})
+	}
+}
+
+func Test_lineScopedRedactor_WriterHoldsBackUnterminatedLine(t *testing.T) {
+	r := NewLineScopedRedactor(func(line string) bool { return strings.HasPrefix(line, "url=") }, NewStore("s3cr3t"))
+
+	out := &bytes.Buffer{}
+	w := NewRedactingWriter(out, r)
+	writeChunked(t, w, "done\nurl="+strings.Repeat("x", 200)+"s3cr3t", 7)
+
+	// only the complete line may be flushed before the line has ended
+	assert.Equal(t, "done\n", out.String())
+
+	require.NoError(t, w.Close())
+	assert.Equal(t, "done\nurl="+strings.Repeat("x", 200)+"*******", out.String())
+}
@@ -3,6 +3,8 @@ package redact
 import (
 	"fmt"
 	"io"
+	"regexp"
+	"sync"
 
 	iface "github.com/anchore/go-logger"
 )
@@ -11,13 +13,17 @@ var _ iface.Logger = (*redactingLogger)(nil)
 var _ iface.Controller = (*redactingLogger)(nil)
 
 type redactingLogger struct {
-	log      iface.MessageLogger
+	log iface.MessageLogger
+	// lock guards the redactor, which may be extended (see New) while other goroutines are logging
+	lock     sync.RWMutex
 	redactor Redactor
 }
 
 func New(log iface.MessageLogger, redactor Redactor) iface.Logger {
 	if r, ok := log.(*redactingLogger); ok {
 		// this is already a redacting logger, so just return it, but attach it to all discovered existing stores
+		r.lock.Lock()
+		defer r.lock.Unlock()
 		r.redactor = newRedactorCollection(r.redactor, redactor)
 		return r
 	}
@@ -27,6 +33,18 @@ func New(log iface.MessageLogger, redactor Redactor) iface.Logger {
 	}
 }
 
+// WithRedactPattern wraps the given logger such that all matches of the given pattern are masked in every message and
+// field before they are logged (see NewRegexRedactor). This is intended for adding a mask at runtime (e.g. during an
+// incident). As with New, when the given logger is already a redacting logger the pattern is added to it, however
+// loggers that were already nested from it are not affected. When the pattern is nil the given logger is returned
+// as-is.
+func WithRedactPattern(l iface.Logger, re *regexp.Regexp) iface.Logger {
+	if re == nil {
+		return l
+	}
+	return New(l, NewRegexRedactor(re))
+}
+
 func (r *redactingLogger) SetOutput(writer io.Writer) {
 	if c, ok := r.log.(iface.Controller); ok {
 		c.SetOutput(writer)
@@ -40,60 +58,88 @@ func (r *redactingLogger) GetOutput() io.Writer {
 	return nil
 }
 
+// Outputs describes the output chain of the wrapped logger, noting that all output is first redacted.
+func (r *redactingLogger) Outputs() []string {
+	outputs := []string{"redacting"}
+	if o, ok := r.log.(interface{ Outputs() []string }); ok {
+		outputs = append(outputs, o.Outputs()...)
+	}
+	return outputs
+}
+
 func (r *redactingLogger) Errorf(format string, args ...interface{}) {
-	r.log.Errorf(r.redactString(format), r.redactFields(args)...)
+	r.log.Errorf("%s", r.redactMessagef(format, args...))
 }
 
 func (r *redactingLogger) Error(args ...interface{}) {
-	r.log.Error(r.redactFields(args)...)
+	r.log.Error(r.redactMessage(args...))
 }
 
 func (r *redactingLogger) Warnf(format string, args ...interface{}) {
-	r.log.Warnf(r.redactString(format), r.redactFields(args)...)
+	r.log.Warnf("%s", r.redactMessagef(format, args...))
 }
 
 func (r *redactingLogger) Warn(args ...interface{}) {
-	r.log.Warn(r.redactFields(args)...)
+	r.log.Warn(r.redactMessage(args...))
 }
 
 func (r *redactingLogger) Infof(format string, args ...interface{}) {
-	r.log.Infof(r.redactString(format), r.redactFields(args)...)
+	r.log.Infof("%s", r.redactMessagef(format, args...))
 }
 
 func (r *redactingLogger) Info(args ...interface{}) {
-	r.log.Info(r.redactFields(args)...)
+	r.log.Info(r.redactMessage(args...))
 }
 
 func (r *redactingLogger) Debugf(format string, args ...interface{}) {
-	r.log.Debugf(r.redactString(format), r.redactFields(args)...)
+	r.log.Debugf("%s", r.redactMessagef(format, args...))
 }
 
 func (r *redactingLogger) Debug(args ...interface{}) {
-	r.log.Debug(r.redactFields(args)...)
+	r.log.Debug(r.redactMessage(args...))
 }
 
 func (r *redactingLogger) Tracef(format string, args ...interface{}) {
-	r.log.Tracef(r.redactString(format), r.redactFields(args)...)
+	r.log.Tracef("%s", r.redactMessagef(format, args...))
 }
 
 func (r *redactingLogger) Trace(args ...interface{}) {
-	r.log.Trace(r.redactFields(args)...)
+	r.log.Trace(r.redactMessage(args...))
+}
+
+func (r *redactingLogger) Logf(level iface.Level, format string, args ...interface{}) {
+	iface.LogfAtLevel(r, level, format, args...)
+}
+
+func (r *redactingLogger) Log(level iface.Level, args ...interface{}) {
+	iface.LogAtLevel(r, level, args...)
 }
 
 func (r *redactingLogger) WithFields(fields ...interface{}) iface.MessageLogger {
 	if l, ok := r.log.(iface.FieldLogger); ok {
-		return New(l.WithFields(r.redactFields(fields)...), r.redactor)
+		return New(l.WithFields(r.redactFields(fields)...), r.getRedactor())
 	}
 	return r
 }
 
 func (r *redactingLogger) Nested(fields ...interface{}) iface.Logger {
 	if l, ok := r.log.(iface.NestedLogger); ok {
-		return New(l.Nested(r.redactFields(fields)...), r.redactor)
+		return New(l.Nested(r.redactFields(fields)...), r.getRedactor())
 	}
 	return r
 }
 
+// redactMessagef formats the message before redacting it, so that secrets spanning the format string and arguments
+// (e.g. "password=%s") are redacted as a whole
+func (r *redactingLogger) redactMessagef(format string, args ...interface{}) string {
+	return r.redactString(fmt.Sprintf(format, args...))
+}
+
+// redactMessage redacts each argument (coercing non-primitive values to strings) and then the message as a whole
+func (r *redactingLogger) redactMessage(args ...interface{}) string {
+	return r.redactString(fmt.Sprint(r.redactFields(args)...))
+}
+
 func (r *redactingLogger) redactFields(fields []interface{}) []interface{} {
 	for i, v := range fields {
 		switch vv := v.(type) {
@@ -127,5 +173,11 @@ func (r *redactingLogger) redactFields(fields []interface{}) []interface{} {
 }
 
 func (r *redactingLogger) redactString(s string) string {
-	return r.redactor.RedactString(s)
+	return r.getRedactor().RedactString(s)
+}
+
+func (r *redactingLogger) getRedactor() Redactor {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.redactor
 }
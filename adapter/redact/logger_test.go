@@ -3,8 +3,12 @@ package redact
 import (
 	"bytes"
 	"fmt"
+	"regexp"
+	"strings"
+	"sync"
 	"testing"
 
+	lr "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -100,3 +104,156 @@ func Test_RedactingLogger(t *testing.T) {
 		})
 	}
 }
+
+func Test_RedactingLogger_Outputs(t *testing.T) {
+	out, err := logrus.New(logrus.Config{
+		EnableConsole: true,
+		Level:         logger.InfoLevel,
+	})
+	require.NoError(t, err)
+
+	l := New(out, NewStore("secret"))
+
+	o, ok := l.(interface{ Outputs() []string })
+	require.True(t, ok)
+	assert.Equal(t, []string{"redacting", "stderr"}, o.Outputs())
+}
+
+// messageRecordingHook records the message of every entry fired
+type messageRecordingHook struct {
+	messages []string
+}
+
+func (h *messageRecordingHook) Levels() []lr.Level {
+	return lr.AllLevels
+}
+
+func (h *messageRecordingHook) Fire(entry *lr.Entry) error {
+	h.messages = append(h.messages, entry.Message)
+	return nil
+}
+
+func Test_RedactingLogger_Hooks(t *testing.T) {
+	hook := &messageRecordingHook{}
+	l, err := logrus.New(logrus.Config{
+		Level: logger.InfoLevel,
+		Hooks: []lr.Hook{hook},
+	})
+	require.NoError(t, err)
+
+	store := NewStore("hunter2")
+	buff := &bytes.Buffer{}
+	w := NewRedactingWriter(buff, store)
+	l.(logger.Controller).SetOutput(w)
+
+	// hooks fire before the output is written, so a redacting writer alone does not redact what hooks see...
+	l.Info("the password is hunter2")
+	require.Equal(t, []string{"the password is hunter2"}, hook.messages)
+
+	// ...however wrapping the logger redacts the message before it reaches the hooks
+	hook.messages = nil
+	New(l, store).Info("the password is hunter2")
+	assert.Equal(t, []string{"the password is *******"}, hook.messages)
+
+	require.NoError(t, w.Close())
+	assert.NotContains(t, buff.String(), "hunter2")
+}
+
+func TestWithRedactPattern(t *testing.T) {
+	out, err := logrus.New(logrus.Config{Level: logger.InfoLevel})
+	require.NoError(t, err)
+	buff := &bytes.Buffer{}
+	out.(logger.Controller).SetOutput(buff)
+
+	l := New(out, NewStore("hunter2"))
+	l.Info("before: password=hunter2 session=sess-1234")
+
+	// adding a pattern at runtime extends the existing redacting logger
+	assert.Same(t, l, WithRedactPattern(l, regexp.MustCompile(`sess-[0-9]+`)))
+	l.Nested("other", "sess-5678").Infof("after: password=hunter2 session=%s", "sess-1234")
+
+	// loggers without redaction are wrapped
+	WithRedactPattern(out, regexp.MustCompile(`tok_[a-z]+`)).Info("token=tok_abc")
+
+	lines := strings.Split(strings.TrimSpace(buff.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Contains(t, lines[0], "before: password=******* session=sess-1234")
+	assert.Contains(t, lines[1], "after: password=******* session=*******")
+	assert.NotContains(t, lines[1], "sess-")
+	assert.Contains(t, lines[2], "token=*******")
+
+	// a nil pattern has no effect
+	assert.Same(t, out, WithRedactPattern(out, nil))
+}
+
+func TestWithRedactPattern_ConcurrentLogging(t *testing.T) {
+	out, err := logrus.New(logrus.Config{Level: logger.InfoLevel})
+	require.NoError(t, err)
+	buff := &syncBuffer{}
+	out.(logger.Controller).SetOutput(buff)
+
+	l := New(out, NewStore("hunter2"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				l.Infof("password=hunter2 token=tok-%d", j)
+				l.Nested("key", "value").Info("password=hunter2")
+			}
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		WithRedactPattern(l, regexp.MustCompile(fmt.Sprintf(`tok-%d\b`, i)))
+	}
+	wg.Wait()
+
+	l.Info("token=tok-3")
+	assert.NotContains(t, buff.String(), "hunter2")
+	assert.Contains(t, buff.String(), "token=*******")
+}
+
+func TestWithRedactPattern_SpansFormatAndArguments(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		log     func(l logger.Logger)
+		want    string
+	}{
+		{
+			name:    "pattern spans a string verb",
+			pattern: `password=\S+`,
+			log:     func(l logger.Logger) { l.Infof("password=%s", "hunter2") },
+			want:    "*******",
+		},
+		{
+			name:    "pattern spans a numeric verb",
+			pattern: `acct-\d+`,
+			log:     func(l logger.Logger) { l.Warnf("acct-%d closed", 123456) },
+			want:    "******* closed",
+		},
+		{
+			name:    "pattern spans arguments",
+			pattern: `acct-\d+`,
+			log:     func(l logger.Logger) { l.Error("acct-", 123456, " closed") },
+			want:    "******* closed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := logrus.New(logrus.Config{Level: logger.InfoLevel})
+			require.NoError(t, err)
+			buff := &bytes.Buffer{}
+			out.(logger.Controller).SetOutput(buff)
+
+			tt.log(WithRedactPattern(out, regexp.MustCompile(tt.pattern)))
+
+			assert.Contains(t, buff.String(), tt.want)
+			assert.NotContains(t, buff.String(), "hunter2")
+			assert.NotContains(t, buff.String(), "123456")
+			assert.NotContains(t, buff.String(), "EXTRA")
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package redact
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// matcher finds every occurrence of a set of values within a string
+type matcher interface {
+	// occurrences returns the span of every occurrence found in s (including occurrences that overlap), in no
+	// particular order
+	occurrences(s string) []span
+}
+
+// markerFunc returns the replacement for a matched value
+type markerFunc func(matched string) string
+
+// fixedMarker replaces every value with the same marker, so that not even the length of a value is revealed
+func fixedMarker(string) string {
+	return redactionMarker
+}
+
+// lengthMarker returns a markerFunc that replaces each value with one "*" per character of the matched text, up to
+// maxLen characters (when maxLen is positive)
+func lengthMarker(maxLen int) markerFunc {
+	return func(matched string) string {
+		n := utf8.RuneCountInString(matched)
+		if maxLen > 0 && n > maxLen {
+			n = maxLen
+		}
+		return strings.Repeat("*", n)
+	}
+}
+
+// span is the [start, end) byte offsets of an occurrence of a value within a string
+type span struct {
+	start, end int
+}
+
+// replaceOccurrences replaces the given occurrences within s with the marker. Occurrences that overlap are merged into a
+// single span before being replaced, so that a value overlapping the end of another value (e.g. "bcdefg" within
+// "abcdefg" alongside "abc") never leaves a tail unredacted. This also returns the length (in bytes) of the longest
+// span replaced (0 when there were none). A nil marker is the fixed redaction marker.
+func replaceOccurrences(s string, spans []span, marker markerFunc) (string, int) {
+	if len(spans) == 0 {
+		return s, 0
+	}
+	if marker == nil {
+		marker = fixedMarker
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].start < spans[j].start
+	})
+
+	var sb strings.Builder
+	sb.Grow(len(s))
+	last, longest := 0, 0
+	for i := 0; i < len(spans); {
+		start, end := spans[i].start, spans[i].end
+		for i++; i < len(spans) && spans[i].start < end; i++ {
+			if spans[i].end > end {
+				end = spans[i].end
+			}
+		}
+		if end-start > longest {
+			longest = end - start
+		}
+		sb.WriteString(s[last:start])
+		sb.WriteString(marker(s[start:end]))
+		last = end
+	}
+	sb.WriteString(s[last:])
+	return sb.String(), longest
+}
+
+// finder returns the [start, end) span of the first occurrence of a value in the given string, or -1 for start
+type finder func(string) (int, int)
+
+// newMatcher compiles a matcher for the given values. Where values overlap, all occurrences are found, so that they
+// can be replaced together (see replaceOccurrences). Only the automaton used for case-sensitive matching avoids making
+// string copies of the values.
+func newMatcher(values [][]byte, caseInsensitive, wholeWord bool) matcher {
+	values = sortByLongest(values)
+
+	if wholeWord {
+		m := make(wholeWordMatcher, 0, len(values))
+		for _, v := range values {
+			m = append(m, newFinder(string(v), caseInsensitive))
+		}
+		return m
+	}
+
+	if caseInsensitive {
+		var quoted []string
+		var literal [][]byte
+		for _, v := range values {
+			if !utf8.Valid(v) {
+				// case has no meaning for invalid UTF-8 (and regexp cannot match it), so match these literally
+				literal = append(literal, v)
+				continue
+			}
+			quoted = append(quoted, regexp.QuoteMeta(string(v)))
+		}
+		if len(quoted) == 0 {
+			return newAutomaton(literal)
+		}
+		// the values are sorted by descending length, so the longest value at any position is the one matched
+		re := foldMatcher{re: regexp.MustCompile(`(?i)(?:` + strings.Join(quoted, "|") + `)`)}
+		if len(literal) == 0 {
+			return re
+		}
+		return multiMatcher{re, newAutomaton(literal)}
+	}
+
+	return newAutomaton(values)
+}
+
+// sortByLongest returns a copy of the values sorted by descending length, then lexically
+func sortByLongest(values [][]byte) [][]byte {
+	sorted := append([][]byte(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if len(sorted[i]) != len(sorted[j]) {
+			return len(sorted[i]) > len(sorted[j])
+		}
+		return bytes.Compare(sorted[i], sorted[j]) < 0
+	})
+	return sorted
+}
+
+func newFinder(value string, caseInsensitive bool) finder {
+	if caseInsensitive && utf8.ValidString(value) {
+		re := regexp.MustCompile(`(?i)` + regexp.QuoteMeta(value))
+		return func(s string) (int, int) {
+			loc := re.FindStringIndex(s)
+			if loc == nil {
+				return -1, -1
+			}
+			return loc[0], loc[1]
+		}
+	}
+	return func(s string) (int, int) {
+		idx := strings.Index(s, value)
+		if idx < 0 {
+			return -1, -1
+		}
+		return idx, idx + len(value)
+	}
+}
+
+// foldMatcher finds the values with a single case-insensitive pattern
+type foldMatcher struct {
+	re *regexp.Regexp
+}
+
+func (m foldMatcher) occurrences(s string) []span {
+	var spans []span
+	for offset := 0; offset < len(s); {
+		loc := m.re.FindStringIndex(s[offset:])
+		if loc == nil || loc[0] == loc[1] {
+			break
+		}
+		start := offset + loc[0]
+		spans = append(spans, span{start: start, end: offset + loc[1]})
+		// resume within the occurrence, as an occurrence of another value may overlap it
+		_, size := utf8.DecodeRuneInString(s[start:])
+		offset = start + size
+	}
+	return spans
+}
+
+// multiMatcher finds the occurrences of every matcher
+type multiMatcher []matcher
+
+func (m multiMatcher) occurrences(s string) []span {
+	var spans []span
+	for _, mm := range m {
+		spans = append(spans, mm.occurrences(s)...)
+	}
+	return spans
+}
+
+// wholeWordMatcher finds occurrences of each value that are not part of a larger word
+type wholeWordMatcher []finder
+
+func (m wholeWordMatcher) occurrences(s string) []span {
+	var spans []span
+	for _, find := range m {
+		for from := 0; from < len(s); {
+			start, end := indexWholeWord(s, from, find)
+			if start < 0 {
+				break
+			}
+			spans = append(spans, span{start: start, end: end})
+			// resume within the occurrence, as another occurrence of the value may overlap it
+			_, size := utf8.DecodeRuneInString(s[start:])
+			from = start + size
+		}
+	}
+	return spans
+}
+
+// indexWholeWord returns the span of the first occurrence in str at or after from that is on word boundaries, or -1
+// for start.
+func indexWholeWord(str string, from int, find finder) (int, int) {
+	offset := from
+	for offset <= len(str) {
+		start, end := find(str[offset:])
+		if start < 0 || end == start {
+			return -1, -1
+		}
+		start += offset
+		end += offset
+		if isWordBoundary(str, start, end) {
+			return start, end
+		}
+		// advance by a single rune, as overlapping occurrences may still match on a boundary
+		_, size := utf8.DecodeRuneInString(str[start:])
+		offset = start + size
+	}
+	return -1, -1
+}
+
+// isWordBoundary reports whether the match at str[start:end] is not joined to neighboring word characters. Edges of
+// the match that are not themselves word characters (e.g. punctuation) need no boundary.
+func isWordBoundary(str string, start, end int) bool {
+	first, _ := utf8.DecodeRuneInString(str[start:end])
+	if isWordRune(first) && start > 0 {
+		before, _ := utf8.DecodeLastRuneInString(str[:start])
+		if isWordRune(before) {
+			return false
+		}
+	}
+	last, _ := utf8.DecodeLastRuneInString(str[start:end])
+	if isWordRune(last) && end < len(str) {
+		after, _ := utf8.DecodeRuneInString(str[end:])
+		if isWordRune(after) {
+			return false
+		}
+	}
+	return true
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,82 @@
+package redact
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+var _ io.WriteCloser = (*multiWriter)(nil)
+
+// Destination is an output along with the redactor to apply to content written to it (if any)
+type Destination struct {
+	Writer io.Writer
+	// Redactor is applied to all content written to this destination. When nil, content is written unredacted.
+	Redactor Redactor
+	// Options configure the redacting writer used for this destination (ignored when there is no Redactor).
+	Options []WriterOption
+}
+
+type multiWriter struct {
+	writers []io.Writer
+	lock    sync.Mutex
+}
+
+// NewMultiWriter returns an io.WriteCloser that writes all content to every destination, each redacted by its own
+// redactor (or unredacted, for destinations without one). This allows the same log output to be written raw to a
+// trusted local file while being redacted for a shared aggregator. Close must be called to flush any content held back
+// by the redacting writers; none of the destination writers are closed.
+func NewMultiWriter(destinations ...Destination) io.WriteCloser {
+	m := &multiWriter{}
+	for _, d := range destinations {
+		if d.Writer == nil {
+			continue
+		}
+		if d.Redactor == nil {
+			m.writers = append(m.writers, d.Writer)
+			continue
+		}
+		m.writers = append(m.writers, NewRedactingWriter(d.Writer, d.Redactor, d.Options...))
+	}
+	return m
+}
+
+func (m *multiWriter) Write(p []byte) (int, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	for _, w := range m.writers {
+		if _, err := w.Write(p); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+func (m *multiWriter) Close() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	var errs []error
+	for _, w := range m.writers {
+		if c, ok := w.(*redactingWriter); ok {
+			if err := c.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// DescribeOutput notes which destinations are redacted.
+func (m *multiWriter) DescribeOutput() []string {
+	var outputs []string
+	for _, w := range m.writers {
+		if _, ok := w.(*redactingWriter); ok {
+			outputs = append(outputs, "redacting")
+		} else {
+			outputs = append(outputs, "unredacted")
+		}
+	}
+	return outputs
+}
@@ -0,0 +1,59 @@
+package redact
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/go-logger"
+	"github.com/anchore/go-logger/adapter/logrus"
+)
+
+func TestNewMultiWriter(t *testing.T) {
+	l, err := logrus.New(logrus.Config{Level: logger.InfoLevel})
+	require.NoError(t, err)
+
+	localFile, err := os.Create(filepath.Join(t.TempDir(), "local.log"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = localFile.Close() })
+
+	aggregator := &bytes.Buffer{}
+	w := NewMultiWriter(
+		Destination{Writer: localFile},
+		Destination{Writer: aggregator, Redactor: NewStore("hunter2")},
+	)
+	l.(logger.Controller).SetOutput(w)
+
+	l.Info("the password is hunter2")
+	require.NoError(t, w.Close())
+
+	local, err := os.ReadFile(localFile.Name())
+	require.NoError(t, err)
+	assert.Contains(t, string(local), "the password is hunter2")
+
+	assert.Contains(t, aggregator.String(), "the password is *******")
+	assert.NotContains(t, aggregator.String(), "hunter2")
+
+	assert.Equal(t, []string{"unredacted", "redacting"}, l.(interface{ Outputs() []string }).Outputs())
+}
+
+func TestNewMultiWriter_RedactorPerDestination(t *testing.T) {
+	first := &bytes.Buffer{}
+	second := &bytes.Buffer{}
+	w := NewMultiWriter(
+		Destination{Writer: first, Redactor: NewStore("alpha")},
+		Destination{Writer: second, Redactor: NewStore("beta")},
+		Destination{Writer: nil},
+	)
+
+	_, err := w.Write([]byte("alpha beta"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, "******* beta", first.String())
+	assert.Equal(t, "alpha *******", second.String())
+}
@@ -0,0 +1,80 @@
+package redact
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/google/uuid"
+)
+
+var _ Redactor = (*regexRedactor)(nil)
+
+// regexRedactor masks all matches of a set of patterns, for secrets that cannot be enumerated ahead of time
+type regexRedactor struct {
+	patterns []*regexp.Regexp
+	// maxMatchLength is a hint for the longest match any pattern could produce (0 when unknown)
+	maxMatchLength int
+	_id            string
+}
+
+// NewRegexRedactor returns a Redactor that replaces every match of the given patterns with the redaction marker.
+// When used with a redacting writer, the longest possible match cannot be derived from a pattern, so the writer's
+// minimum window size bounds how long of a match split across writes is still caught (see
+// NewRegexRedactorWithMaxLength and WithMinWindowSize).
+func NewRegexRedactor(patterns ...*regexp.Regexp) Redactor {
+	return NewRegexRedactorWithMaxLength(0, patterns...)
+}
+
+// NewRegexRedactorWithMaxLength is NewRegexRedactor with a hint for the longest match (in bytes) any pattern could
+// produce, allowing a redacting writer to size its window such that matches split across writes are still caught.
+func NewRegexRedactorWithMaxLength(maxMatchLength int, patterns ...*regexp.Regexp) Redactor {
+	var ps []*regexp.Regexp
+	for _, p := range patterns {
+		if p != nil {
+			ps = append(ps, p)
+		}
+	}
+	return &regexRedactor{
+		patterns:       ps,
+		maxMatchLength: maxMatchLength,
+		_id:            uuid.New().String(),
+	}
+}
+
+// NewPatternRedactor compiles the given patterns and returns a Redactor as with NewRegexRedactor.
+func NewPatternRedactor(patterns ...string) (Redactor, error) {
+	var compiled []*regexp.Regexp
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", p, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return NewRegexRedactor(compiled...), nil
+}
+
+func (r *regexRedactor) id() string {
+	return r._id
+}
+
+func (r *regexRedactor) RedactString(str string) string {
+	for _, p := range r.patterns {
+		str = p.ReplaceAllLiteralString(str, redactionMarker)
+	}
+	return str
+}
+
+// redactLongest is RedactString, also returning the length of the longest match found (0 when there were none)
+func (r *regexRedactor) redactLongest(str string) (string, int) {
+	longest := 0
+	for _, p := range r.patterns {
+		str = p.ReplaceAllStringFunc(str, func(matched string) string {
+			if len(matched) > longest {
+				longest = len(matched)
+			}
+			return redactionMarker
+		})
+	}
+	return str, longest
+}
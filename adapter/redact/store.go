@@ -1,25 +1,40 @@
 package redact
 
 import (
-	"strings"
+	"bytes"
+	"hash/maphash"
 	"sync"
 
 	"github.com/google/uuid"
 	"github.com/scylladb/go-set/strset"
 )
 
+// redactionMarker is the fixed replacement for any redacted value
+const redactionMarker = "*******"
+
 type Store interface {
 	Redactor
 	StoreWriter
+	ValueProvider
 }
 
+// Redactor masks secrets within strings. Redactors may be implemented outside of this package (e.g. to redact values
+// held elsewhere), in which case they should also implement ValueProvider when possible.
 type Redactor interface {
 	RedactString(string) string
-	identifiable
+}
+
+// ValueProvider may be implemented by a Redactor to expose the literal values that it redacts. A redacting writer uses
+// these to hold back enough content to catch values split across writes, so a custom Redactor that redacts values
+// longer than the minimum window of the writer should implement this.
+type ValueProvider interface {
+	Values() []string
 }
 
 type StoreWriter interface {
 	Add(value ...string)
+	AddBytes(value ...[]byte)
+	Remove(value ...string)
 	identifiable
 }
 
@@ -31,17 +46,22 @@ type identifiable interface {
 type redactorCollection []Redactor
 
 var _ Redactor = (*redactorCollection)(nil)
+var _ ValueProvider = (*redactorCollection)(nil)
 
 func newRedactorCollection(readers ...Redactor) Redactor {
 	collection := make(redactorCollection, 0, len(readers))
 	ids := strset.New()
 	addReader := func(rs ...Redactor) {
 		for _, r := range rs {
-			if ids.Has(r.id()) {
-				continue
+			// redactors from this package are only added once, however there is no way to tell whether other
+			// redactors are the same, so these are always added
+			if i, ok := r.(identifiable); ok {
+				if ids.Has(i.id()) {
+					continue
+				}
+				ids.Add(i.id())
 			}
 			collection = append(collection, r)
-			ids.Add(r.id())
 		}
 	}
 	for _, r := range readers {
@@ -61,28 +81,95 @@ func (c redactorCollection) RedactString(s string) string {
 	return s
 }
 
+// Values returns the values of all redactors in the collection that expose them
+func (c redactorCollection) Values() []string {
+	var values []string
+	for _, r := range c {
+		values = append(values, getRedactorValues(r)...)
+	}
+	return values
+}
+
 func (c redactorCollection) id() (val string) {
 	for _, r := range c {
-		val += r.id()
+		if i, ok := r.(identifiable); ok {
+			val += i.id()
+		}
 	}
 	return val
 }
 
 // store maintains a list of redactions, and implements Redactor Redact* methods
 type store struct {
-	redactions *strset.Set
+	// redactions holds the store's own copy of every value, bucketed by hash. Values are held as []byte (rather than
+	// strings) so that they can be wiped when removed.
+	redactions map[uint64][][]byte
+	seed       maphash.Seed
 	lock       *sync.RWMutex
 	_id        string
+	wholeWord  bool
+	// caseInsensitive matches values regardless of case, while values are still stored as provided
+	caseInsensitive bool
+	// marker returns the replacement for each matched value (the fixed redaction marker when nil)
+	marker markerFunc
+	// version is incremented whenever the set of redactions changes
+	version uint64
+	// matcher is compiled from the redactions as of matcherVersion, and is shared by all callers (e.g. every
+	// redactingWriter using this store) until the redactions change
+	matcher        matcher
+	matcherVersion uint64
+	// matcherBuilds is the number of times the matcher has been compiled
+	matcherBuilds uint64
 }
 
 var _ Store = (*store)(nil)
 
+// StoreOption configures how a Store matches values
+type StoreOption func(*store)
+
+// WithWholeWord only redacts values that are not part of a larger word, where word characters are any Unicode letter
+// or digit. For example, with this option the value "pass" would be redacted in "pass=x" but not in "password".
+func WithWholeWord() StoreOption {
+	return func(s *store) {
+		s.wholeWord = true
+	}
+}
+
+// WithCaseInsensitive redacts values regardless of case (e.g. the value "deadbeef" would also redact "DEADBEEF").
+// Values are still stored (and reported) as they were provided.
+func WithCaseInsensitive() StoreOption {
+	return func(s *store) {
+		s.caseInsensitive = true
+	}
+}
+
+// WithLengthMarker replaces each value with one "*" per character of the matched text (up to maxLen characters when
+// maxLen is positive) instead of the fixed length marker. This keeps columns aligned and distinguishes redacted values
+// of different lengths, however it reveals the length of each secret (which the fixed marker does not).
+func WithLengthMarker(maxLen int) StoreOption {
+	return func(s *store) {
+		s.marker = lengthMarker(maxLen)
+	}
+}
+
 func NewStore(values ...string) Store {
-	return &store{
-		redactions: strset.New(values...),
+	return NewStoreWithOptions(values)
+}
+
+// NewStoreWithOptions creates a Store with the given values, configured by the given options.
+func NewStoreWithOptions(values []string, opts ...StoreOption) Store {
+	s := &store{
+		redactions: make(map[uint64][][]byte),
+		seed:       maphash.MakeSeed(),
 		lock:       &sync.RWMutex{},
 		_id:        uuid.New().String(),
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	// values are added through Add so that the same validation applies as to values added later
+	s.Add(values...)
+	return s
 }
 
 func (w *store) id() string {
@@ -93,24 +180,133 @@ func (w *store) Add(values ...string) {
 	w.lock.Lock()
 	defer w.lock.Unlock()
 	for _, value := range values {
-		if len(value) <= 1 {
-			// smallest possible redaction string must be larger than 1 character
+		// the conversion is already a copy, which the store can own
+		w.add([]byte(value), false)
+	}
+}
+
+// AddBytes adds the given values as with Add, for secrets that are held as []byte (e.g. read from a keyring). Each new
+// value is copied into the store exactly once, so callers may (and should) zero their own buffers as soon as this
+// returns. The store's copy is wiped when the value is removed. Note that Values returns string copies (which cannot
+// be wiped), as does matching with WithCaseInsensitive or WithWholeWord.
+func (w *store) AddBytes(values ...[]byte) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	for _, value := range values {
+		w.add(value, true)
+	}
+}
+
+// add adds the value unless it is already present, first copying it when clone is true (otherwise the store takes
+// ownership of the given slice). The lock must be held.
+func (w *store) add(value []byte, clone bool) {
+	if len(value) <= 1 {
+		// smallest possible redaction string must be larger than 1 character
+		return
+	}
+	hash, idx := w.indexOf(value)
+	if idx >= 0 {
+		return
+	}
+	if clone {
+		value = bytes.Clone(value)
+	}
+	w.redactions[hash] = append(w.redactions[hash], value)
+	w.version++
+}
+
+// indexOf returns the hash of the value along with its position within the bucket for that hash (or -1 when it is not
+// present). The lock must be held.
+func (w *store) indexOf(value []byte) (uint64, int) {
+	hash := maphash.Bytes(w.seed, value)
+	for i, v := range w.redactions[hash] {
+		if bytes.Equal(v, value) {
+			return hash, i
+		}
+	}
+	return hash, -1
+}
+
+// Remove stops redacting the given values, wiping the store's copy of each. Note that any content a redacting writer
+// is still holding back at the time of removal is no longer redacted for these values when it is flushed.
+func (w *store) Remove(values ...string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	for _, value := range values {
+		hash, idx := w.indexOf([]byte(value))
+		if idx < 0 {
 			continue
 		}
-		w.redactions.Add(value)
+		bucket := w.redactions[hash]
+		clear(bucket[idx])
+		bucket[idx] = bucket[len(bucket)-1]
+		bucket[len(bucket)-1] = nil
+		if len(bucket) == 1 {
+			delete(w.redactions, hash)
+		} else {
+			w.redactions[hash] = bucket[:len(bucket)-1]
+		}
+		w.version++
+		// release the matcher compiled with the value now, rather than when it is next rebuilt
+		w.matcher = nil
 	}
 }
 
-func (w *store) values() []string {
+// currentVersion returns the version of the redactions, which is incremented whenever they change
+func (w *store) currentVersion() uint64 {
+	w.lock.RLock()
+	defer w.lock.RUnlock()
+	return w.version
+}
+
+// Values returns copies of all values being redacted
+func (w *store) Values() []string {
 	w.lock.RLock()
 	defer w.lock.RUnlock()
-	return w.redactions.List()
+	values := make([]string, 0, len(w.redactions))
+	for _, bucket := range w.redactions {
+		for _, v := range bucket {
+			values = append(values, string(v))
+		}
+	}
+	return values
 }
 
 func (w *store) RedactString(str string) string {
-	for _, s := range w.values() {
-		// note: we don't use the length of the redaction string to determine the replacement string, as even the length could be considered sensitive
-		str = strings.ReplaceAll(str, s, strings.Repeat("*", 7))
+	redacted, _ := w.redactLongest(str)
+	return redacted
+}
+
+// redactLongest is RedactString, also returning the length of the longest span replaced (0 when there were none)
+func (w *store) redactLongest(str string) (string, int) {
+	return replaceOccurrences(str, w.getMatcher().occurrences(str), w.marker)
+}
+
+// list returns the store's own copy of every value. The lock must be held.
+func (w *store) list() [][]byte {
+	values := make([][]byte, 0, len(w.redactions))
+	for _, bucket := range w.redactions {
+		values = append(values, bucket...)
+	}
+	return values
+}
+
+// getMatcher returns a matcher for all redactions, compiling a new one only when the set of redactions has changed
+// since the last compilation.
+func (w *store) getMatcher() matcher {
+	w.lock.RLock()
+	m, current := w.matcher, w.matcher != nil && w.matcherVersion == w.version
+	w.lock.RUnlock()
+	if current {
+		return m
+	}
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	if w.matcher == nil || w.matcherVersion != w.version {
+		w.matcher = newMatcher(w.list(), w.caseInsensitive, w.wholeWord)
+		w.matcherVersion = w.version
+		w.matcherBuilds++
 	}
-	return str
+	return w.matcher
 }
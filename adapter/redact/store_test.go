@@ -0,0 +1,385 @@
+package redact
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_store_RedactString_WholeWord(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		input  string
+		want   string
+	}{
+		{
+			name:   "standalone ascii word",
+			values: []string{"pass"},
+			input:  "pass=x password=y",
+			want:   "*******=x password=y",
+		},
+		{
+			name:   "unicode letters are word characters",
+			values: []string{"clé"},
+			input:  "clé: cléf éclé",
+			want:   "*******: cléf éclé",
+		},
+		{
+			name:   "non-latin neighbors prevent a match",
+			values: []string{"секрет"},
+			input:  "секрет секретный несекрет",
+			want:   "******* секретный несекрет",
+		},
+		{
+			name:   "unicode digits are word characters",
+			values: []string{"abc"},
+			input:  "abc٣ abc",
+			want:   "abc٣ *******",
+		},
+		{
+			name:   "cjk punctuation is a boundary",
+			values: []string{"秘密"},
+			input:  "「秘密」と秘密値",
+			want:   "「*******」と秘密値",
+		},
+		{
+			name:   "non-word edges in the value need no boundary",
+			values: []string{"-tok"},
+			input:  "x-tok y-toky",
+			want:   "x******* y-toky",
+		},
+		{
+			name:   "later occurrence on a boundary is still matched",
+			values: []string{"ab"},
+			input:  "aab ab",
+			want:   "aab *******",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewStoreWithOptions(tt.values, WithWholeWord())
+			assert.Equal(t, tt.want, s.RedactString(tt.input))
+		})
+	}
+}
+
+func Test_store_RedactString_SubstringByDefault(t *testing.T) {
+	s := NewStore("pass")
+	assert.Equal(t, "*******=x *******word=y", s.RedactString("pass=x password=y"))
+}
+
+func FuzzRedactString(f *testing.F) {
+	f.Add("secret\nsecretkey", "this secretkey is a secret")
+	f.Add("秘密\npass", "秘密 password")
+	f.Add("", "empty secrets should not change anything")
+	f.Add("a\nb", "single characters are never redacted")
+
+	f.Fuzz(func(t *testing.T, secrets string, input string) {
+		values := fuzzSecrets(secrets)
+		s := NewStore(values...)
+
+		got := s.RedactString(input)
+		for _, v := range s.(*store).Values() {
+			assert.NotContains(t, got, v)
+		}
+	})
+}
+
+// fuzzSecrets splits the given newline delimited secrets, omitting secrets that contain the redaction marker character
+// (a secret made of asterisks could legitimately be found within the redaction marker itself)
+func fuzzSecrets(secrets string) []string {
+	var values []string
+	for _, v := range strings.Split(secrets, "\n") {
+		if strings.Contains(v, "*") {
+			continue
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
+func Test_store_MatcherIsCachedUntilValuesChange(t *testing.T) {
+	s := NewStore("secret", "hunter2").(*store)
+
+	assert.Equal(t, "******* *******", s.RedactString("secret hunter2"))
+	assert.Equal(t, "*******", s.RedactString("secret"))
+	assert.Equal(t, uint64(1), s.matcherBuilds)
+
+	// adding an existing value does not invalidate the matcher
+	s.Add("secret")
+	assert.Equal(t, "*******", s.RedactString("secret"))
+	assert.Equal(t, uint64(1), s.matcherBuilds)
+
+	s.Add("another")
+	assert.Equal(t, "******* *******", s.RedactString("secret another"))
+	assert.Equal(t, uint64(2), s.matcherBuilds)
+}
+
+func Benchmark_store_SharedMatcher(b *testing.B) {
+	var values []string
+	for i := 0; i < 100; i++ {
+		values = append(values, fmt.Sprintf("secret-value-%03d", i))
+	}
+	s := NewStore(values...).(*store)
+
+	writers := make([]io.WriteCloser, 8)
+	for i := range writers {
+		writers[i] = NewRedactingWriter(io.Discard, s)
+	}
+	line := []byte("some log line that mentions secret-value-042 and secret-value-099 along the way\n")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = writers[i%len(writers)].Write(line)
+	}
+	b.StopTimer()
+
+	for _, w := range writers {
+		require.NoError(b, w.Close())
+	}
+	require.Equal(b, uint64(1), s.matcherBuilds)
+}
+
+func Test_store_RedactString_CaseInsensitive(t *testing.T) {
+	tests := []struct {
+		name      string
+		values    []string
+		wholeWord bool
+		input     string
+		want      string
+	}{
+		{
+			name:   "differently cased occurrences",
+			values: []string{"deadbeef"},
+			input:  "token deadbeef echoed as DEADBEEF and DeadBeef",
+			want:   "token ******* echoed as ******* and *******",
+		},
+		{
+			name:   "unicode case folding",
+			values: []string{"straße", "ключ"},
+			input:  "STRAßE КЛЮЧ Ключ",
+			want:   "******* ******* *******",
+		},
+		{
+			name:   "case variant with a different byte length",
+			values: []string{"key"},
+			input:  "\u212aEY",
+			want:   "*******",
+		},
+		{
+			name:      "combined with whole word",
+			values:    []string{"pass"},
+			wholeWord: true,
+			input:     "PASS=x Password=y",
+			want:      "*******=x Password=y",
+		},
+		{
+			name:   "invalid utf-8 values are matched literally",
+			values: []string{"ab\xffcd", "token"},
+			input:  "ab\xffcd TOKEN",
+			want:   "******* *******",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := []StoreOption{WithCaseInsensitive()}
+			if tt.wholeWord {
+				opts = append(opts, WithWholeWord())
+			}
+			s := NewStoreWithOptions(tt.values, opts...)
+			assert.Equal(t, tt.want, s.RedactString(tt.input))
+
+			// the stored values remain as provided
+			assert.ElementsMatch(t, tt.values, s.(*store).Values())
+		})
+	}
+}
+
+func Test_store_RedactString_CaseSensitiveByDefault(t *testing.T) {
+	s := NewStore("deadbeef")
+	assert.Equal(t, "******* DEADBEEF", s.RedactString("deadbeef DEADBEEF"))
+}
+
+func Test_store_RedactString_OverlappingValues(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		input  string
+		want   string
+	}{
+		{
+			name:   "short value is a prefix of a longer value",
+			values: []string{"secret", "secretkey"},
+			input:  "the secretkey and the secret",
+			want:   "the ******* and the *******",
+		},
+		{
+			name:   "short value is a suffix of a longer value",
+			values: []string{"key", "secretkey"},
+			input:  "the secretkey and the key",
+			want:   "the ******* and the *******",
+		},
+		{
+			name:   "longest value wins at the same position",
+			values: []string{"ab", "abc", "abcd"},
+			input:  "abcd abc ab",
+			want:   "******* ******* *******",
+		},
+		{
+			name:   "value overlaps the end of another value",
+			values: []string{"ab-cd", "cd-ef"},
+			input:  "x ab-cd-ef x",
+			want:   "x ******* x",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, opts := range [][]StoreOption{nil, {WithCaseInsensitive()}, {WithWholeWord()}} {
+				// the result must not depend on insertion order
+				forward := NewStoreWithOptions(tt.values, opts...)
+				reversed := NewStoreWithOptions(nil, opts...)
+				for i := len(tt.values) - 1; i >= 0; i-- {
+					reversed.Add(tt.values[i])
+				}
+
+				assert.Equal(t, tt.want, forward.RedactString(tt.input))
+				assert.Equal(t, tt.want, reversed.RedactString(tt.input))
+			}
+		})
+	}
+}
+
+func Test_store_RedactString_NoTailOfOverlappingValueSurvives(t *testing.T) {
+	for _, opts := range [][]StoreOption{nil, {WithCaseInsensitive()}} {
+		s := NewStoreWithOptions([]string{"abc", "bcdefg"}, opts...)
+		assert.Equal(t, "*******", s.RedactString("abcdefg"))
+	}
+
+	s := NewStoreWithOptions([]string{"abc", "bcdefg"}, WithCaseInsensitive())
+	assert.Equal(t, "x ******* x", s.RedactString("x ABCdefG x"))
+}
+
+func Test_store_RedactString_LongestValueFullyRedactedRegardlessOfOrder(t *testing.T) {
+	// repeat to cover set iteration order, which varies between runs
+	for i := 0; i < 50; i++ {
+		s := NewStore("secret")
+		s.Add("secretkey")
+		assert.Equal(t, "*******", s.RedactString("secretkey"))
+
+		s = NewStore("secretkey")
+		s.Add("secret")
+		assert.Equal(t, "*******", s.RedactString("secretkey"))
+	}
+}
+
+func Test_store_Remove(t *testing.T) {
+	s := NewStore("secret", "hunter2").(*store)
+	assert.Equal(t, "******* *******", s.RedactString("secret hunter2"))
+
+	// removing a value that was never added does not invalidate the matcher
+	s.Remove("missing")
+	assert.Equal(t, "******* *******", s.RedactString("secret hunter2"))
+	assert.Equal(t, uint64(1), s.matcherBuilds)
+
+	s.Remove("secret")
+	assert.Equal(t, "secret *******", s.RedactString("secret hunter2"))
+	assert.Equal(t, []string{"hunter2"}, s.Values())
+	assert.Equal(t, uint64(2), s.matcherBuilds)
+}
+
+func Test_store_AddBytes(t *testing.T) {
+	secret := []byte("hunter2")
+	s := NewStore().(*store)
+	s.AddBytes(secret, []byte("x"), nil)
+	assert.Equal(t, []string{"hunter2"}, s.Values())
+
+	// the store keeps its own copy, so the caller can wipe theirs immediately
+	for i := range secret {
+		secret[i] = 0
+	}
+	assert.Equal(t, "the password is *******", s.RedactString("the password is hunter2"))
+
+	// adding an existing value (from either form) does not invalidate the matcher
+	s.AddBytes([]byte("hunter2"))
+	s.Add("hunter2")
+	assert.Equal(t, "*******", s.RedactString("hunter2"))
+	assert.Equal(t, uint64(1), s.matcherBuilds)
+}
+
+func Test_store_Remove_WipesValue(t *testing.T) {
+	s := NewStore().(*store)
+	s.AddBytes([]byte("hunter2"), []byte("swordfish"))
+
+	values := s.list()
+	require.Len(t, values, 2)
+	var owned []byte
+	for _, v := range values {
+		if string(v) == "hunter2" {
+			owned = v
+		}
+	}
+	require.NotNil(t, owned)
+
+	s.Remove("hunter2")
+
+	assert.Equal(t, make([]byte, len("hunter2")), owned)
+	assert.Equal(t, []string{"swordfish"}, s.Values())
+	assert.Equal(t, "hunter2 *******", s.RedactString("hunter2 swordfish"))
+}
+
+func Test_store_RedactString_LengthMarker(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []string
+		opts   []StoreOption
+		input  string
+		want   string
+	}{
+		{
+			name:   "marker matches the length of each value",
+			values: []string{"abc", "longer-secret"},
+			opts:   []StoreOption{WithLengthMarker(0)},
+			input:  "a=abc b=longer-secret",
+			want:   "a=*** b=*************",
+		},
+		{
+			name:   "marker is capped",
+			values: []string{"abc", "longer-secret"},
+			opts:   []StoreOption{WithLengthMarker(5)},
+			input:  "a=abc b=longer-secret",
+			want:   "a=*** b=*****",
+		},
+		{
+			name:   "length is counted in characters",
+			values: []string{"ключ"},
+			opts:   []StoreOption{WithLengthMarker(0)},
+			input:  "k=ключ",
+			want:   "k=****",
+		},
+		{
+			name:   "case insensitive uses the length of the matched text",
+			values: []string{"key", "token"},
+			opts:   []StoreOption{WithLengthMarker(0), WithCaseInsensitive()},
+			input:  "KEY Token",
+			want:   "*** *****",
+		},
+		{
+			name:   "whole word",
+			values: []string{"pass"},
+			opts:   []StoreOption{WithLengthMarker(0), WithWholeWord()},
+			input:  "pass=x password=y",
+			want:   "****=x password=y",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewStoreWithOptions(tt.values, tt.opts...)
+			assert.Equal(t, tt.want, s.RedactString(tt.input))
+		})
+	}
+}
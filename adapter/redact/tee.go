@@ -0,0 +1,54 @@
+package redact
+
+import (
+	"io"
+	"sync"
+)
+
+var _ io.WriteCloser = (*teeWriter)(nil)
+
+// teeWriter writes all content unredacted to a restricted writer, and redacted to a general writer
+type teeWriter struct {
+	unredacted io.Writer
+	redacted   io.WriteCloser
+	lock       sync.Mutex
+}
+
+// NewTeeWriter returns an io.WriteCloser that writes all content redacted to the general writer and, when a
+// restricted writer is given, also writes the same content UNREDACTED to the restricted writer. This allows a single
+// logger output (e.g. set via SetOutput) to feed both a broadly shared log and a tightly controlled raw log for
+// investigations. The restricted writer is optional and may be nil. Close must be called to flush any content held
+// back from the general writer; neither wrapped writer is closed.
+func NewTeeWriter(general io.Writer, r Redactor, restricted io.Writer, opts ...WriterOption) io.WriteCloser {
+	return &teeWriter{
+		unredacted: restricted,
+		redacted:   NewRedactingWriter(general, r, opts...),
+	}
+}
+
+func (t *teeWriter) Write(p []byte) (int, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.unredacted != nil {
+		if _, err := t.unredacted.Write(p); err != nil {
+			return 0, err
+		}
+	}
+	return t.redacted.Write(p)
+}
+
+func (t *teeWriter) Close() error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.redacted.Close()
+}
+
+// DescribeOutput notes that output is redacted, and whether an unredacted copy is also being written.
+func (t *teeWriter) DescribeOutput() []string {
+	if t.unredacted == nil {
+		return []string{"redacting"}
+	}
+	return []string{"redacting", "unredacted-tee"}
+}
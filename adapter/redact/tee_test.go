@@ -0,0 +1,41 @@
+package redact
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/anchore/go-logger"
+	"github.com/anchore/go-logger/adapter/logrus"
+)
+
+func TestNewTeeWriter(t *testing.T) {
+	l, err := logrus.New(logrus.Config{Level: logger.InfoLevel})
+	require.NoError(t, err)
+
+	general := &bytes.Buffer{}
+	restricted := &bytes.Buffer{}
+	w := NewTeeWriter(general, NewStore("hunter2"), restricted)
+	l.(logger.Controller).SetOutput(w)
+
+	l.Info("the password is hunter2")
+	require.NoError(t, w.Close())
+
+	assert.Contains(t, restricted.String(), "the password is hunter2")
+	assert.Contains(t, general.String(), "the password is *******")
+	assert.NotContains(t, general.String(), "hunter2")
+	assert.Equal(t, []string{"redacting", "unredacted-tee"}, l.(interface{ Outputs() []string }).Outputs())
+}
+
+func TestNewTeeWriter_NoRestrictedWriter(t *testing.T) {
+	general := &bytes.Buffer{}
+	w := NewTeeWriter(general, NewStore("hunter2"), nil)
+
+	_, err := w.Write([]byte("the password is hunter2"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, "the password is *******", general.String())
+}
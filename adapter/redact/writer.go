@@ -0,0 +1,593 @@
+package redact
+
+import (
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// defaultMinWindowSize is the default smallest window of buffered bytes, used when the redactor does not expose any
+// values (or pattern length hints) to size the window from
+const defaultMinWindowSize = 64
+
+var _ RedactingWriter = (*redactingWriter)(nil)
+
+// RedactingWriter is an io.WriteCloser that redacts all content before writing it to a wrapped writer
+type RedactingWriter interface {
+	io.WriteCloser
+	// Reset discards any held back content and rebinds the writer to the given writer and redactor (keeping all
+	// options), allowing writers to be reused (e.g. with a sync.Pool). Close should be called before Reset to flush
+	// any held back content. Reset must not be called while the writer is otherwise in use.
+	Reset(w io.Writer, r Redactor)
+	// Stats describes the secrets seen by the writer, to help with sizing its window.
+	Stats() WriterStats
+}
+
+// CutAdjuster may be implemented by a Redactor whose matches cannot be described by values or patterns alone (e.g.
+// those that depend on a preceding key or delimiter, or on the rest of the line). Before flushing, a redacting writer
+// gives AdjustCut all buffered content and the position it intends to flush up to; the redactor returns that position
+// or an earlier one, such that redacting the content before it gives the same result as redacting the content as a
+// whole, no matter what is written afterwards. Custom redactors that neither implement this nor expose their values
+// (see ValueProvider) only catch secrets split across writes when they are shorter than half of the window.
+type CutAdjuster interface {
+	AdjustCut(content string, cut int) int
+}
+
+// WriterStats describes the secrets seen by a redacting writer
+type WriterStats struct {
+	// WindowSize is the number of bytes buffered before any content is flushed (half of which is held back between
+	// writes), based on the current values of the redactor.
+	WindowSize int
+	// LongestValue is the length in bytes of the longest value (or pattern match length hint) of the redactor.
+	LongestValue int
+	// LongestMatch is the length in bytes of the longest secret actually redacted since the writer was created (or
+	// reset). Only values of stores and matches of regex redactors are reported. Pattern matches without a length
+	// hint approaching half of the WindowSize signal that such matches split across writes may be missed.
+	LongestMatch int
+}
+
+// redactingWriter is an io.Writer that redacts all content before passing it to the wrapped writer. Since a secret
+// may be split across multiple Write calls, a trailing window of bytes is held back until enough subsequent content
+// has been written to rule out a partial secret (or until Close is called).
+type redactingWriter struct {
+	writer        io.Writer
+	redactor      Redactor
+	minWindowSize int
+	maxWindowSize int
+	buf           []byte
+	lock          sync.Mutex
+	// values and maxLen are cached from the redactor as of valuesVersion, and are only recomputed when the values
+	// of the redactor change
+	values        []string
+	maxLen        int
+	valuesVersion uint64
+	valuesCached  bool
+	// longestMatch is the length of the longest secret redacted since creation (or the last reset)
+	longestMatch int
+}
+
+// WriterOption configures a redacting writer
+type WriterOption func(*redactingWriter)
+
+// WithMinWindowSize sets the smallest window of bytes buffered before any content is flushed (half of which is held
+// back between writes). Secrets that cannot be enumerated (e.g. regex matches without a length hint) that are split
+// across writes are only caught when shorter than half of this window.
+func WithMinWindowSize(n int) WriterOption {
+	return func(w *redactingWriter) {
+		if n > 0 {
+			w.minWindowSize = n
+		}
+	}
+}
+
+// WithMaxWindowSize caps the window of buffered bytes, which is otherwise twice the length of the longest secret (so a
+// single 1MB secret would cause 2MB to be buffered before anything is flushed). With a cap, secrets longer than half
+// of the window are instead tracked as they are written: content is only held back beyond the window while the end of
+// the buffer could be the start of such a secret, until the secret has been ruled out or has been written in full and
+// is clear of the window (or on Close). This bounds buffering (and so latency) for ordinary content, at the cost of
+// scanning for partial long secrets on each write. Matches of patterns (which cannot be tracked this way) that are
+// longer than half of the window may be missed when split across writes.
+func WithMaxWindowSize(n int) WriterOption {
+	return func(w *redactingWriter) {
+		if n > 0 {
+			w.maxWindowSize = n
+		}
+	}
+}
+
+// NewRedactingWriter returns an io.WriteCloser that redacts all content written to it before writing to the given
+// writer. Close must be called to flush any held back content; it does not close the wrapped writer. Secrets split
+// across writes are caught for redactors that expose their values or patterns, or that implement CutAdjuster; for
+// any other redactor, only secrets shorter than half of the window are.
+func NewRedactingWriter(w io.Writer, r Redactor, opts ...WriterOption) RedactingWriter {
+	rw := &redactingWriter{
+		writer:        w,
+		redactor:      r,
+		minWindowSize: defaultMinWindowSize,
+	}
+	for _, opt := range opts {
+		opt(rw)
+	}
+	return rw
+}
+
+func (w *redactingWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.buf = append(w.buf, p...)
+
+	fold := foldsCase(w.redactor)
+	values, maxLen := w.redactorValues(fold)
+	window := w.windowSize(maxLen)
+	if len(w.buf) <= window {
+		return len(p), nil
+	}
+
+	// hold back enough bytes to contain any secret that has only been partially written so far
+	cut := w.safeCut(len(w.buf)-window/2, values, getRedactorPatterns(w.redactor), fold, matchesWholeWord(w.redactor))
+	if cut <= 0 {
+		return len(p), nil
+	}
+
+	if err := w.flush(cut); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// windowSize returns the number of bytes to buffer before flushing, given the length of the longest value
+func (w *redactingWriter) windowSize(maxLen int) int {
+	if l := maxPatternLength(w.redactor); l > maxLen {
+		maxLen = l
+	}
+	window := 2 * maxLen
+	if window < w.minWindowSize {
+		window = w.minWindowSize
+	}
+	if w.maxWindowSize > 0 && window > w.maxWindowSize {
+		window = w.maxWindowSize
+	}
+	return window
+}
+
+func (w *redactingWriter) Stats() WriterStats {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	_, maxLen := w.redactorValues(foldsCase(w.redactor))
+	longestValue := maxLen
+	if l := maxPatternLength(w.redactor); l > longestValue {
+		longestValue = l
+	}
+	return WriterStats{
+		WindowSize:   w.windowSize(maxLen),
+		LongestValue: longestValue,
+		LongestMatch: w.longestMatch,
+	}
+}
+
+func (w *redactingWriter) Reset(writer io.Writer, r Redactor) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.writer = writer
+	w.redactor = r
+	// the buffer is reused, so wipe any (unredacted) content that was held back
+	clear(w.buf)
+	w.buf = w.buf[:0]
+	w.values = nil
+	w.maxLen = 0
+	w.valuesVersion = 0
+	w.valuesCached = false
+	w.longestMatch = 0
+}
+
+// Close redacts and writes any held back content. The wrapped writer is not closed.
+func (w *redactingWriter) Close() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	return w.flush(len(w.buf))
+}
+
+// flush redacts and writes the first n buffered bytes, keeping the remainder buffered
+func (w *redactingWriter) flush(n int) error {
+	if n == 0 {
+		return nil
+	}
+	redacted, longest := redactLongest(w.redactor, string(w.buf[:n]))
+	if longest > w.longestMatch {
+		w.longestMatch = longest
+	}
+	w.buf = append(w.buf[:0], w.buf[n:]...)
+	_, err := io.WriteString(w.writer, redacted)
+	return err
+}
+
+// safeCut moves the given cut position earlier until no occurrence of any value (or match of any pattern) straddles
+// it, so that redacting the content before the cut yields the same result as redacting the content as a whole. For
+// whole word matching, whether an occurrence is redacted also depends on the characters on either side of it, so
+// occurrences that start or end at the cut are held back too, along with the character preceding them. Redactors that
+// implement CutAdjuster may move the cut earlier still.
+func (w *redactingWriter) safeCut(cut int, values []string, patterns []*regexp.Regexp, fold, wholeWord bool) int {
+	content := string(w.buf)
+
+	var matches [][]int
+	for _, p := range patterns {
+		matches = append(matches, p.FindAllStringIndex(content, -1)...)
+	}
+
+	for moved := true; moved && cut > 0; {
+		moved = false
+		for _, m := range matches {
+			if m[0] < cut && m[1] > cut {
+				cut = m[0]
+				moved = true
+			}
+		}
+		for _, v := range values {
+			var start int
+			if fold {
+				start = straddlingIndexFold(content, v, cut, wholeWord)
+			} else {
+				start = straddlingIndex(content, v, cut, wholeWord)
+			}
+			if start < 0 && w.maxWindowSize > 0 {
+				// the window may be smaller than this value, so it may have only been partially written so far
+				start = pendingIndex(content, v, cut, fold, wholeWord)
+			}
+			if start >= 0 {
+				if wholeWord && start > 0 {
+					// keep the preceding character with the occurrence, so the boundary can still be checked
+					_, size := utf8.DecodeLastRuneInString(content[:start])
+					start -= size
+				}
+				cut = start
+				moved = true
+			}
+		}
+		if adjusted := adjustCut(w.redactor, content, cut); adjusted < cut {
+			cut = adjusted
+			moved = true
+		}
+	}
+	return cut
+}
+
+// straddlingIndex returns the start of an occurrence of value within content that begins before the cut and ends
+// after it, or -1 if there is no such occurrence. When touching is true, occurrences that start or end exactly at the
+// cut are also returned.
+func straddlingIndex(content, value string, cut int, touching bool) int {
+	if value == "" {
+		return -1
+	}
+	from := cut - len(value) + 1
+	to := cut + len(value) - 1
+	if touching {
+		from--
+		to++
+	}
+	if from < 0 {
+		from = 0
+	}
+	if to > len(content) {
+		to = len(content)
+	}
+	if from >= to {
+		return -1
+	}
+	idx := strings.Index(content[from:to], value)
+	if idx < 0 {
+		return -1
+	}
+	return from + idx
+}
+
+// pendingIndex returns the earliest position before the cut where the remainder of content is the start of (but not
+// all of) the given value, or -1 if there is no such position. When touching is true, the position at the cut is also
+// considered.
+func pendingIndex(content, value string, cut int, fold, touching bool) int {
+	if value == "" {
+		return -1
+	}
+	maxLen := len(value)
+	if fold {
+		maxLen = maxFoldLength(value)
+	}
+	from := len(content) - maxLen + 1
+	if from < 0 {
+		from = 0
+	}
+	end := cut
+	if touching {
+		end++
+	}
+	if end > len(content) {
+		end = len(content)
+	}
+	for i := from; i < end; i++ {
+		if fold {
+			if utf8.RuneStart(content[i]) && hasPartialPrefixFold(value, content[i:]) {
+				return i
+			}
+			continue
+		}
+		// skip ahead to the next candidate start
+		next := strings.IndexByte(content[i:end], value[0])
+		if next < 0 {
+			return -1
+		}
+		i += next
+		if len(content)-i < len(value) && strings.HasPrefix(value, content[i:]) {
+			return i
+		}
+	}
+	return -1
+}
+
+// hasPartialPrefixFold reports whether prefix is the start of (but not all of) value regardless of case
+func hasPartialPrefixFold(value, prefix string) bool {
+	for len(prefix) > 0 {
+		if value == "" {
+			return false
+		}
+		pr, psize := utf8.DecodeRuneInString(prefix)
+		vr, vsize := utf8.DecodeRuneInString(value)
+		if !equalFoldRune(pr, vr) {
+			return false
+		}
+		prefix = prefix[psize:]
+		value = value[vsize:]
+	}
+	return value != ""
+}
+
+// straddlingIndexFold is straddlingIndex, matching the value regardless of case
+func straddlingIndexFold(content, value string, cut int, touching bool) int {
+	if value == "" {
+		return -1
+	}
+	from := cut - maxFoldLength(value) + 1
+	last := cut - 1
+	if touching {
+		from--
+		last++
+	}
+	if from < 0 {
+		from = 0
+	}
+	for i := from; i <= last && i < len(content); i++ {
+		if !utf8.RuneStart(content[i]) {
+			continue
+		}
+		if end, ok := matchFoldAt(content, i, value); ok && (end > cut || touching && end == cut) {
+			return i
+		}
+	}
+	return -1
+}
+
+// matchFoldAt reports whether value matches content at the given offset regardless of case, returning the end of
+// the match within content
+func matchFoldAt(content string, offset int, value string) (int, bool) {
+	i := offset
+	for _, vr := range value {
+		if i >= len(content) {
+			return 0, false
+		}
+		cr, size := utf8.DecodeRuneInString(content[i:])
+		if !equalFoldRune(cr, vr) {
+			return 0, false
+		}
+		i += size
+	}
+	return i, true
+}
+
+// equalFoldRune reports whether the runes are equal under simple Unicode case folding (as with (?i) in regexp)
+func equalFoldRune(a, b rune) bool {
+	if a == b {
+		return true
+	}
+	for r := unicode.SimpleFold(a); r != a; r = unicode.SimpleFold(r) {
+		if r == b {
+			return true
+		}
+	}
+	return false
+}
+
+// maxFoldLength returns the largest number of bytes any case variant of the value could be encoded as (e.g. "k"
+// may also be matched by the three byte Kelvin sign)
+func maxFoldLength(value string) int {
+	total := 0
+	for _, r := range value {
+		longest := utf8.RuneLen(r)
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			if l := utf8.RuneLen(f); l > longest {
+				longest = l
+			}
+		}
+		total += longest
+	}
+	return total
+}
+
+// redactorValues returns all values of the redactor along with the length of the longest, only recomputing these when
+// the values of the redactor have changed since the last call
+func (w *redactingWriter) redactorValues(fold bool) ([]string, int) {
+	version, versioned := redactorVersion(w.redactor)
+	if versioned && w.valuesCached && w.valuesVersion == version {
+		return w.values, w.maxLen
+	}
+
+	w.values = getRedactorValues(w.redactor)
+	w.maxLen = maxSecretLength(w.values, fold)
+	w.valuesVersion = version
+	w.valuesCached = versioned
+	return w.values, w.maxLen
+}
+
+func maxSecretLength(values []string, fold bool) int {
+	maxLen := 0
+	for _, v := range values {
+		l := len(v)
+		if fold {
+			l = maxFoldLength(v)
+		}
+		if l > maxLen {
+			maxLen = l
+		}
+	}
+	return maxLen
+}
+
+// redactorVersion returns a value that changes whenever the values of the given redactor change. Since store
+// versions only ever increase, the sum of all versions within a collection changes whenever any one of them does.
+func redactorVersion(r Redactor) (uint64, bool) {
+	switch v := r.(type) {
+	case *store:
+		return v.currentVersion(), true
+	case redactorCollection:
+		var sum uint64
+		for _, rr := range v {
+			version, ok := redactorVersion(rr)
+			if !ok {
+				return 0, false
+			}
+			sum += version
+		}
+		return sum, true
+	case *lineScopedRedactor:
+		return redactorVersion(v.inner)
+	}
+	if _, ok := r.(ValueProvider); ok {
+		// there is no way to tell when the values of other redactors change, so they must be fetched on every write
+		return 0, false
+	}
+	// all other redactors have no values to report, so never change
+	return 0, true
+}
+
+// foldsCase reports whether the given redactor matches any values regardless of case
+func foldsCase(r Redactor) bool {
+	switch v := r.(type) {
+	case *store:
+		return v.caseInsensitive
+	case *lineScopedRedactor:
+		return foldsCase(v.inner)
+	case redactorCollection:
+		for _, rr := range v {
+			if foldsCase(rr) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// adjustCut moves the cut earlier as needed by the given redactor (or any redactor within a collection)
+func adjustCut(r Redactor, content string, cut int) int {
+	switch v := r.(type) {
+	case redactorCollection:
+		for _, rr := range v {
+			cut = adjustCut(rr, content, cut)
+		}
+		return cut
+	case CutAdjuster:
+		if adjusted := v.AdjustCut(content, cut); adjusted < cut {
+			return adjusted
+		}
+	}
+	return cut
+}
+
+// matchesWholeWord reports whether the given redactor only matches any values on word boundaries
+func matchesWholeWord(r Redactor) bool {
+	switch v := r.(type) {
+	case *store:
+		return v.wholeWord
+	case *lineScopedRedactor:
+		return matchesWholeWord(v.inner)
+	case redactorCollection:
+		for _, rr := range v {
+			if matchesWholeWord(rr) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// redactLongest redacts the string, also returning the length of the longest secret found when the redactor can report
+// it (0 otherwise)
+func redactLongest(r Redactor, s string) (string, int) {
+	switch v := r.(type) {
+	case *store:
+		return v.redactLongest(s)
+	case *regexRedactor:
+		return v.redactLongest(s)
+	case redactorCollection:
+		longest := 0
+		for _, rr := range v {
+			var l int
+			s, l = redactLongest(rr, s)
+			if l > longest {
+				longest = l
+			}
+		}
+		return s, longest
+	}
+	return r.RedactString(s), 0
+}
+
+// getRedactorPatterns returns all patterns that the given redactor will redact matches of
+func getRedactorPatterns(r Redactor) []*regexp.Regexp {
+	switch v := r.(type) {
+	case *regexRedactor:
+		return v.patterns
+	case *lineScopedRedactor:
+		return getRedactorPatterns(v.inner)
+	case redactorCollection:
+		var patterns []*regexp.Regexp
+		for _, rr := range v {
+			patterns = append(patterns, getRedactorPatterns(rr)...)
+		}
+		return patterns
+	}
+	return nil
+}
+
+// maxPatternLength returns the largest match length hint of any pattern based redactor (0 when unknown)
+func maxPatternLength(r Redactor) int {
+	switch v := r.(type) {
+	case *regexRedactor:
+		return v.maxMatchLength
+	case *lineScopedRedactor:
+		return maxPatternLength(v.inner)
+	case redactorCollection:
+		maxLen := 0
+		for _, rr := range v {
+			if l := maxPatternLength(rr); l > maxLen {
+				maxLen = l
+			}
+		}
+		return maxLen
+	}
+	return 0
+}
+
+// getRedactorValues returns all literal values that the given redactor will redact (when they can be determined)
+func getRedactorValues(r Redactor) []string {
+	if v, ok := r.(*lineScopedRedactor); ok {
+		return getRedactorValues(v.inner)
+	}
+	if v, ok := r.(ValueProvider); ok {
+		return v.Values()
+	}
+	return nil
+}
@@ -0,0 +1,519 @@
+package redact
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_redactingWriter(t *testing.T) {
+	longSecret := strings.Repeat("s3cr3t", 20)
+
+	tests := []struct {
+		name      string
+		values    []string
+		input     string
+		chunkSize int
+		want      string
+	}{
+		{
+			name:      "single write",
+			values:    []string{"hunter2"},
+			input:     "the password is hunter2 ok",
+			chunkSize: 100,
+			want:      "the password is ******* ok",
+		},
+		{
+			name:      "secret split across writes",
+			values:    []string{"hunter2"},
+			input:     strings.Repeat("x", 100) + "hunter2" + strings.Repeat("y", 100),
+			chunkSize: 3,
+			want:      strings.Repeat("x", 100) + "*******" + strings.Repeat("y", 100),
+		},
+		{
+			name:      "secret longer than the default window split across writes",
+			values:    []string{longSecret},
+			input:     strings.Repeat("a", 150) + longSecret + strings.Repeat("b", 150) + longSecret,
+			chunkSize: 7,
+			want:      strings.Repeat("a", 150) + "*******" + strings.Repeat("b", 150) + "*******",
+		},
+		{
+			name:      "overlapping values split across writes",
+			values:    []string{"secret", "secretkey"},
+			input:     strings.Repeat("a", 70) + "secretkey " + strings.Repeat("b", 70) + "secret",
+			chunkSize: 1,
+			want:      strings.Repeat("a", 70) + "******* " + strings.Repeat("b", 70) + "*******",
+		},
+		{
+			name:      "no values",
+			values:    nil,
+			input:     strings.Repeat("a", 200),
+			chunkSize: 9,
+			want:      strings.Repeat("a", 200),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			w := NewRedactingWriter(out, NewStore(tt.values...))
+
+			writeChunked(t, w, tt.input, tt.chunkSize)
+			require.NoError(t, w.Close())
+
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func Test_redactingWriter_HoldsBackPartialSecrets(t *testing.T) {
+	out := &bytes.Buffer{}
+	w := NewRedactingWriter(out, NewStore("hunter2"))
+
+	writeChunked(t, w, strings.Repeat("x", 200)+"hunt", 10)
+
+	// content well before the window is flushed, but the partial secret is held back
+	assert.NotEmpty(t, out.String())
+	assert.NotContains(t, out.String(), "hunt")
+
+	writeChunked(t, w, "er2", 10)
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, strings.Repeat("x", 200)+"*******", out.String())
+}
+
+func Test_redactingWriter_DynamicSecretAddition(t *testing.T) {
+	out := &bytes.Buffer{}
+	s := NewStore("first")
+	w := NewRedactingWriter(out, s)
+
+	writeChunked(t, w, "first "+strings.Repeat("a", 10), 4)
+	s.Add("second-value")
+	writeChunked(t, w, "second-value first", 4)
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, "******* "+strings.Repeat("a", 10)+"******* *******", out.String())
+}
+
+func writeChunked(t *testing.T, w interface{ Write([]byte) (int, error) }, input string, chunkSize int) {
+	t.Helper()
+	data := []byte(input)
+	for len(data) > 0 {
+		n := chunkSize
+		if n > len(data) {
+			n = len(data)
+		}
+		written, err := w.Write(data[:n])
+		require.NoError(t, err)
+		require.Equal(t, n, written)
+		data = data[n:]
+	}
+}
+
+func FuzzRedactingWriter(f *testing.F) {
+	f.Add("hunter2", strings.Repeat("x", 100)+"hunter2"+strings.Repeat("y", 100), []byte{3, 50, 1})
+	f.Add("secret\nsecretkey", "a secretkey and a secret", []byte{1})
+	f.Add("秘密", strings.Repeat("秘", 70)+"秘密", []byte{5, 2})
+
+	f.Fuzz(func(t *testing.T, secrets string, input string, splits []byte) {
+		values := fuzzSecrets(secrets)
+		s := NewStore(values...)
+
+		// a tiny maximum window forces every secret to be tracked as it is written
+		for _, opts := range [][]WriterOption{nil, {WithMinWindowSize(1), WithMaxWindowSize(2)}} {
+			out := &bytes.Buffer{}
+			w := NewRedactingWriter(out, s, opts...)
+
+			data := []byte(input)
+			for i := 0; len(data) > 0; i++ {
+				n := len(data)
+				if len(splits) > 0 {
+					n = int(splits[i%len(splits)]) + 1
+					if n > len(data) {
+						n = len(data)
+					}
+				}
+				_, err := w.Write(data[:n])
+				require.NoError(t, err)
+				data = data[n:]
+			}
+			require.NoError(t, w.Close())
+
+			got := out.String()
+			tracked := s.(*store).Values()
+			for _, v := range tracked {
+				assert.NotContains(t, got, v)
+			}
+			if len(tracked) == 1 {
+				// with a single secret the result is independent of replacement order, so streaming must match
+				// redacting the input as a whole
+				assert.Equal(t, s.RedactString(input), got)
+			}
+		}
+	})
+}
+
+func Test_redactingWriter_CaseInsensitive(t *testing.T) {
+	secret := strings.Repeat("deadbeef", 6)
+
+	tests := []struct {
+		name      string
+		input     string
+		chunkSize int
+		want      string
+	}{
+		{
+			name:      "differently cased secret split across writes",
+			input:     strings.Repeat("x", 100) + strings.ToUpper(secret) + strings.Repeat("y", 100) + secret,
+			chunkSize: 5,
+			want:      strings.Repeat("x", 100) + "*******" + strings.Repeat("y", 100) + "*******",
+		},
+		{
+			name: "case variant encoded with more bytes than the stored value",
+			// the Kelvin sign is a three byte case variant of "k"
+			input:     strings.Repeat("x", 100) + strings.Repeat("\u212a", 30) + strings.Repeat("y", 100),
+			chunkSize: 1,
+			want:      strings.Repeat("x", 100) + "*******" + strings.Repeat("y", 100),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			w := NewRedactingWriter(out, NewStoreWithOptions([]string{secret, strings.Repeat("k", 30)}, WithCaseInsensitive()))
+
+			writeChunked(t, w, tt.input, tt.chunkSize)
+			require.NoError(t, w.Close())
+
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func Test_redactingWriter_RemoveShrinksWindow(t *testing.T) {
+	out := &bytes.Buffer{}
+	long := strings.Repeat("L", 100)
+	s := NewStore(long, "short")
+	w := NewRedactingWriter(out, s)
+
+	// the window is sized by the longest secret, so nothing is flushed yet
+	_, err := w.Write([]byte("short " + strings.Repeat("a", 144)))
+	require.NoError(t, err)
+	assert.Empty(t, out.String())
+
+	s.Remove(long)
+
+	// the window is now the default minimum, so all but half of it is flushed on the next write
+	_, err = w.Write([]byte("b"))
+	require.NoError(t, err)
+	assert.Equal(t, "******* "+strings.Repeat("a", 145-defaultMinWindowSize/2), out.String())
+	assert.Len(t, w.(*redactingWriter).buf, defaultMinWindowSize/2)
+
+	// later writes do not buffer more than the shrunken window
+	_, err = w.Write([]byte(strings.Repeat("c", 100)))
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(w.(*redactingWriter).buf), defaultMinWindowSize)
+
+	require.NoError(t, w.Close())
+	assert.Equal(t, "******* "+strings.Repeat("a", 144)+"b"+strings.Repeat("c", 100), out.String())
+}
+
+func Test_redactingWriter_ValuesCachedUntilStoreChanges(t *testing.T) {
+	out := &bytes.Buffer{}
+	s := NewStore("first")
+	other := NewStore("other")
+	w := NewRedactingWriter(out, newRedactorCollection(s, other)).(*redactingWriter)
+
+	writeChunked(t, w, "first", 2)
+	cached := w.values
+	assert.ElementsMatch(t, []string{"first", "other"}, cached)
+
+	// stable values are not recomputed
+	writeChunked(t, w, "first", 2)
+	assert.Equal(t, &cached[0], &w.values[0])
+
+	// a change to any store within the collection invalidates the cache
+	other.Add(strings.Repeat("x", 100))
+	writeChunked(t, w, "!", 1)
+	assert.ElementsMatch(t, []string{"first", "other", strings.Repeat("x", 100)}, w.values)
+	assert.Equal(t, 100, w.maxLen)
+
+	s.Remove("first")
+	writeChunked(t, w, "!", 1)
+	assert.ElementsMatch(t, []string{"other", strings.Repeat("x", 100)}, w.values)
+}
+
+func Benchmark_redactingWriter_SmallWrites(b *testing.B) {
+	var values []string
+	for i := 0; i < 100; i++ {
+		values = append(values, fmt.Sprintf("secret-value-%03d", i))
+	}
+	w := NewRedactingWriter(io.Discard, NewStore(values...))
+	chunk := []byte("a short write ")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = w.Write(chunk)
+	}
+	b.StopTimer()
+	require.NoError(b, w.Close())
+}
+
+func Test_redactingWriter_MaxWindowSize(t *testing.T) {
+	const maxWindow = 128
+	secret := strings.Repeat("0123456789abcdef", 64) // 1KB
+
+	tests := []struct {
+		name string
+		opts []StoreOption
+	}{
+		{name: "case sensitive"},
+		{name: "case insensitive", opts: []StoreOption{WithCaseInsensitive()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			w := NewRedactingWriter(out, NewStoreWithOptions([]string{secret}, tt.opts...), WithMaxWindowSize(maxWindow)).(*redactingWriter)
+
+			assertBounded := func(input string) {
+				t.Helper()
+				for i := 0; i < len(input); i += 8 {
+					end := i + 8
+					if end > len(input) {
+						end = len(input)
+					}
+					_, err := w.Write([]byte(input[i:end]))
+					require.NoError(t, err)
+					require.LessOrEqual(t, len(w.buf), maxWindow)
+				}
+			}
+
+			// ordinary content is streamed through a bounded window, even when it contains partial secrets
+			assertBounded(strings.Repeat("x", 1000))
+			assertBounded(secret[:100] + strings.Repeat("y", 1000))
+
+			// while the secret is being written (and until it is clear of the window) it is held back in full
+			writeChunked(t, w, secret+strings.Repeat("z", maxWindow/2), 8)
+			assertBounded(strings.Repeat("z", 1000-maxWindow/2))
+
+			require.NoError(t, w.Close())
+			want := strings.Repeat("x", 1000) + secret[:100] + strings.Repeat("y", 1000) + "*******" + strings.Repeat("z", 1000)
+			assert.Equal(t, want, out.String())
+		})
+	}
+}
+
+// valueRedactor is a custom Redactor that exposes its values, implementing only exported methods (as a Redactor from
+// another package must)
+type valueRedactor struct {
+	value string
+}
+
+func (r valueRedactor) RedactString(s string) string {
+	return strings.ReplaceAll(s, r.value, "[redacted]")
+}
+
+func (r valueRedactor) Values() []string {
+	return []string{r.value}
+}
+
+func Test_redactingWriter_CustomValueProvider(t *testing.T) {
+	secret := strings.Repeat("s3cr3t", 30)
+	input := strings.Repeat("x", 100) + secret + strings.Repeat("y", 100)
+	want := strings.Repeat("x", 100) + "[redacted]" + strings.Repeat("y", 100)
+
+	tests := []struct {
+		name     string
+		redactor Redactor
+	}{
+		{
+			name:     "custom redactor",
+			redactor: valueRedactor{value: secret},
+		},
+		{
+			name:     "custom redactor within a collection",
+			redactor: newRedactorCollection(NewStore("other"), valueRedactor{value: secret}),
+		},
+		{
+			name:     "custom redactors added to a collection",
+			redactor: newRedactorCollection(newRedactorCollection(valueRedactor{value: "other"}), valueRedactor{value: secret}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			w := NewRedactingWriter(out, tt.redactor)
+			writeChunked(t, w, input, 7)
+			require.NoError(t, w.Close())
+
+			assert.Equal(t, want, out.String())
+		})
+	}
+}
+
+// bracketRedactor is a custom Redactor that masks everything between angle brackets, which cannot be described by
+// values or patterns, so it tells the writer where it is safe to cut instead
+type bracketRedactor struct{}
+
+func (bracketRedactor) RedactString(s string) string {
+	return regexp.MustCompile(`<[^>]*>`).ReplaceAllString(s, "<[redacted]>")
+}
+
+func (bracketRedactor) AdjustCut(content string, cut int) int {
+	open := strings.LastIndexByte(content[:cut], '<')
+	if open >= 0 && !strings.Contains(content[open:cut], ">") {
+		return open
+	}
+	return cut
+}
+
+func Test_redactingWriter_CustomCutAdjuster(t *testing.T) {
+	input := strings.Repeat("x", 100) + "<" + strings.Repeat("s3cr3t", 30) + ">" + strings.Repeat("y", 100)
+	want := strings.Repeat("x", 100) + "<[redacted]>" + strings.Repeat("y", 100)
+
+	tests := []struct {
+		name     string
+		redactor Redactor
+	}{
+		{
+			name:     "custom redactor",
+			redactor: bracketRedactor{},
+		},
+		{
+			name:     "custom redactor within a collection",
+			redactor: newRedactorCollection(NewStore("other"), bracketRedactor{}),
+		},
+		{
+			name:     "custom redactor within a line scoped redactor",
+			redactor: NewLineScopedRedactor(func(string) bool { return true }, bracketRedactor{}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, size := range []int{1, 7, 40} {
+				out := &bytes.Buffer{}
+				w := NewRedactingWriter(out, tt.redactor)
+				writeChunked(t, w, input, size)
+				require.NoError(t, w.Close())
+
+				assert.Equal(t, want, out.String(), "chunk size %d", size)
+			}
+		})
+	}
+}
+
+func Test_redactingWriter_Reset(t *testing.T) {
+	first := &bytes.Buffer{}
+	w := NewRedactingWriter(first, NewStore("first-secret"), WithMinWindowSize(16))
+
+	writeChunked(t, w, "stream one: first-secret second-secret", 5)
+	require.NoError(t, w.Close())
+	assert.Equal(t, "stream one: ******* second-secret", first.String())
+
+	// content held back without Close is discarded by Reset rather than leaking into the next stream
+	writeChunked(t, w, "unflushed", 3)
+
+	second := &bytes.Buffer{}
+	w.Reset(second, NewStore("second-secret"))
+	writeChunked(t, w, "stream two: first-secret second-secret "+strings.Repeat("x", 40), 5)
+	require.NoError(t, w.Close())
+
+	assert.Equal(t, "stream two: first-secret ******* "+strings.Repeat("x", 40), second.String())
+	assert.Equal(t, "stream one: ******* second-secret", first.String())
+	assert.Equal(t, 16, w.(*redactingWriter).minWindowSize, "options are kept")
+}
+
+func Test_redactingWriter_LengthMarker(t *testing.T) {
+	short := "s3cr3t"
+	long := strings.Repeat("0123456789", 9)
+
+	input := strings.Repeat("x", 100) + short + strings.Repeat("y", 50) + long + strings.Repeat("z", 10) + short + long
+	want := strings.Repeat("x", 100) + strings.Repeat("*", len(short)) + strings.Repeat("y", 50) + strings.Repeat("*", len(long)) +
+		strings.Repeat("z", 10) + strings.Repeat("*", len(short)) + strings.Repeat("*", len(long))
+
+	for _, chunkSize := range []int{1, 3, 7, 64, len(input)} {
+		t.Run(fmt.Sprintf("chunk size %d", chunkSize), func(t *testing.T) {
+			out := &bytes.Buffer{}
+			w := NewRedactingWriter(out, NewStoreWithOptions([]string{short, long}, WithLengthMarker(0)))
+
+			writeChunked(t, w, input, chunkSize)
+			require.NoError(t, w.Close())
+
+			assert.Equal(t, want, out.String())
+		})
+	}
+}
+
+func Test_redactingWriter_WholeWord(t *testing.T) {
+	input := strings.Repeat("pass password xpass pass=x passpass Pass ", 10)
+
+	tests := []struct {
+		name string
+		opts []StoreOption
+	}{
+		{
+			name: "case sensitive",
+			opts: []StoreOption{WithWholeWord()},
+		},
+		{
+			name: "case insensitive",
+			opts: []StoreOption{WithWholeWord(), WithCaseInsensitive()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewStoreWithOptions([]string{"pass"}, tt.opts...)
+			want := s.RedactString(input)
+			require.NotContains(t, want, "*******word")
+
+			// a small window places the cut at every position relative to each occurrence (including directly
+			// before and after), where the neighboring characters decide whether an occurrence is a whole word
+			for _, opts := range [][]WriterOption{
+				{WithMinWindowSize(1)},
+				{WithMinWindowSize(8)},
+				{WithMinWindowSize(13)},
+				{WithMinWindowSize(1), WithMaxWindowSize(2)},
+			} {
+				for chunkSize := 1; chunkSize <= 20; chunkSize++ {
+					out := &bytes.Buffer{}
+					w := NewRedactingWriter(out, s, opts...)
+
+					writeChunked(t, w, input, chunkSize)
+					require.NoError(t, w.Close())
+
+					require.Equal(t, want, out.String(), "chunk=%d", chunkSize)
+				}
+			}
+		})
+	}
+}
+
+func Test_redactingWriter_Stats(t *testing.T) {
+	s := NewStore("short1", strings.Repeat("l", 40))
+	r := newRedactorCollection(s, NewRegexRedactor(regexp.MustCompile(`tok_[a-z]+`)))
+	out := &bytes.Buffer{}
+	w := NewRedactingWriter(out, r)
+
+	assert.Equal(t, WriterStats{WindowSize: 80, LongestValue: 40}, w.Stats())
+
+	writeChunked(t, w, "a short1 value "+strings.Repeat("x", 100), 7)
+	assert.Equal(t, 6, w.Stats().LongestMatch)
+
+	// pattern matches are reported too, which may be longer than any value
+	writeChunked(t, w, "token tok_"+strings.Repeat("a", 50)+" "+strings.Repeat("x", 100), 7)
+	require.NoError(t, w.Close())
+	assert.Equal(t, WriterStats{WindowSize: 80, LongestValue: 40, LongestMatch: 54}, w.Stats())
+
+	// only the longest match is kept
+	writeChunked(t, w, "short1", 7)
+	require.NoError(t, w.Close())
+	assert.Equal(t, 54, w.Stats().LongestMatch)
+
+	w.Reset(out, s)
+	assert.Equal(t, WriterStats{WindowSize: 80, LongestValue: 40}, w.Stats())
+}
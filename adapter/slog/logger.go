@@ -0,0 +1,318 @@
+package slog
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"math"
+	"os"
+	"sync"
+
+	iface "github.com/anchore/go-logger"
+	"github.com/anchore/go-logger/internal/logfile"
+)
+
+var _ iface.Logger = (*logger)(nil)
+var _ iface.Controller = (*logger)(nil)
+var _ iface.LevelEnabler = (*logger)(nil)
+
+const (
+	// LevelTrace is the slog level used for trace logging, which slog does not define (one step below slog.LevelDebug)
+	LevelTrace = slog.LevelDebug - 4
+
+	// levelDisabled is above any level that is ever logged
+	levelDisabled = slog.Level(math.MaxInt32)
+)
+
+// Config contains all configurable values for the slog logger
+type Config struct {
+	EnableConsole bool
+	FileLocation  string
+	// Level is the most verbose level to log at. Any spelling accepted by iface.LevelFromString may be used (e.g.
+	// "INFO" or "warning"). An unset level defaults to info, and an unrecognized level is an error.
+	Level iface.Level
+	// JSON selects slog's JSON handler instead of the text handler when no Handler is provided
+	JSON bool
+	// Handler is an optional pre-built handler (e.g. a slog.JSONHandler) to emit records to. When provided, the
+	// handler owns where records are written, so EnableConsole, FileLocation, and JSON are ignored, and SetOutput has
+	// no effect. Records are still filtered by Level before reaching the handler.
+	Handler slog.Handler
+	// TruncateFiles discards any existing content of the log file when opened, otherwise entries are appended.
+	TruncateFiles bool
+	// FilePermissions is used when creating the log file (defaults to 0644).
+	FilePermissions fs.FileMode
+}
+
+func DefaultConfig() Config {
+	return Config{
+		EnableConsole: true,
+		FileLocation:  "",
+		Level:         iface.InfoLevel,
+	}
+}
+
+// logger adapts a slog.Logger to the go-logger interface
+type logger struct {
+	logger *slog.Logger
+	output *output
+}
+
+// New creates a new logger with the given configuration
+func New(cfg Config) (iface.Logger, error) {
+	normalized, err := normalizeLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+	level := getLogLevel(normalized)
+
+	if cfg.Handler != nil {
+		return &logger{
+			logger: slog.New(&levelHandler{level: level, handler: cfg.Handler}),
+		}, nil
+	}
+
+	var w io.Writer
+	switch {
+	case cfg.EnableConsole && cfg.FileLocation != "":
+		logFile, err := logfile.Open(cfg.FileLocation, cfg.TruncateFiles, cfg.FilePermissions)
+		if err != nil {
+			return nil, fmt.Errorf("unable to setup log file: %w", err)
+		}
+		w = io.MultiWriter(os.Stderr, logFile)
+	case cfg.EnableConsole:
+		w = os.Stderr
+	case cfg.FileLocation != "":
+		logFile, err := logfile.Open(cfg.FileLocation, cfg.TruncateFiles, cfg.FilePermissions)
+		if err != nil {
+			return nil, fmt.Errorf("unable to setup log file: %w", err)
+		}
+		w = logFile
+	default:
+		w = io.Discard
+	}
+
+	out := &output{writer: w}
+	opts := &slog.HandlerOptions{
+		Level:       level,
+		ReplaceAttr: replaceLevelName,
+	}
+
+	var h slog.Handler
+	if cfg.JSON {
+		h = slog.NewJSONHandler(out, opts)
+	} else {
+		h = slog.NewTextHandler(out, opts)
+	}
+
+	return &logger{
+		logger: slog.New(h),
+		output: out,
+	}, nil
+}
+
+// NewFromSlogHandler creates a logger that emits records directly to the given slog.Handler.
+func NewFromSlogHandler(h slog.Handler) iface.Logger {
+	return &logger{
+		logger: slog.New(h),
+	}
+}
+
+// Tracef takes a formatted template string and template arguments for the trace logging level.
+func (l *logger) Tracef(format string, args ...interface{}) {
+	l.logf(LevelTrace, format, args...)
+}
+
+// Debugf takes a formatted template string and template arguments for the debug logging level.
+func (l *logger) Debugf(format string, args ...interface{}) {
+	l.logf(slog.LevelDebug, format, args...)
+}
+
+// Infof takes a formatted template string and template arguments for the info logging level.
+func (l *logger) Infof(format string, args ...interface{}) {
+	l.logf(slog.LevelInfo, format, args...)
+}
+
+// Warnf takes a formatted template string and template arguments for the warning logging level.
+func (l *logger) Warnf(format string, args ...interface{}) {
+	l.logf(slog.LevelWarn, format, args...)
+}
+
+// Errorf takes a formatted template string and template arguments for the error logging level.
+func (l *logger) Errorf(format string, args ...interface{}) {
+	l.logf(slog.LevelError, format, args...)
+}
+
+// Trace logs the given arguments at the trace logging level.
+func (l *logger) Trace(args ...interface{}) {
+	l.log(LevelTrace, args...)
+}
+
+// Debug logs the given arguments at the debug logging level.
+func (l *logger) Debug(args ...interface{}) {
+	l.log(slog.LevelDebug, args...)
+}
+
+// Info logs the given arguments at the info logging level.
+func (l *logger) Info(args ...interface{}) {
+	l.log(slog.LevelInfo, args...)
+}
+
+// Warn logs the given arguments at the warning logging level.
+func (l *logger) Warn(args ...interface{}) {
+	l.log(slog.LevelWarn, args...)
+}
+
+// Error logs the given arguments at the error logging level.
+func (l *logger) Error(args ...interface{}) {
+	l.log(slog.LevelError, args...)
+}
+
+// Logf takes a formatted template string and template arguments for the given logging level.
+func (l *logger) Logf(level iface.Level, format string, args ...interface{}) {
+	iface.LogfAtLevel(l, level, format, args...)
+}
+
+// Log logs the given arguments at the given logging level.
+func (l *logger) Log(level iface.Level, args ...interface{}) {
+	iface.LogAtLevel(l, level, args...)
+}
+
+// WithFields returns a message logger with multiple key-value fields attached as slog attributes.
+func (l *logger) WithFields(fields ...interface{}) iface.MessageLogger {
+	return &logger{logger: l.logger.With(getAttrs(fields...)...), output: l.output}
+}
+
+// Nested returns a logger that attaches the given key-value fields (along with any from this logger) to all records.
+func (l *logger) Nested(fields ...interface{}) iface.Logger {
+	return &logger{logger: l.logger.With(getAttrs(fields...)...), output: l.output}
+}
+
+// SetOutput changes where records are written for this logger and all loggers nested from it. This has no effect
+// when the logger was created from a pre-built slog.Handler.
+func (l *logger) SetOutput(writer io.Writer) {
+	if l.output == nil {
+		return
+	}
+	l.output.set(writer)
+}
+
+func (l *logger) GetOutput() io.Writer {
+	if l.output == nil {
+		return nil
+	}
+	return l.output.get()
+}
+
+// IsLevelEnabled reports whether messages at the given level are logged.
+func (l *logger) IsLevelEnabled(level iface.Level) bool {
+	return iface.IsLevel(level, iface.Levels()...) && l.logger.Enabled(context.Background(), getLogLevel(level))
+}
+
+func (l *logger) logf(level slog.Level, format string, args ...interface{}) {
+	ctx := context.Background()
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+	l.logger.Log(ctx, level, fmt.Sprintf(format, args...))
+}
+
+func (l *logger) log(level slog.Level, args ...interface{}) {
+	ctx := context.Background()
+	if !l.logger.Enabled(ctx, level) {
+		return
+	}
+	l.logger.Log(ctx, level, fmt.Sprint(args...))
+}
+
+// getAttrs converts key-value pairs (and any iface.Fields maps found among them) into slog attributes
+func getAttrs(fields ...interface{}) []interface{} {
+	var attrs []interface{}
+	iface.RangeFields(fields, func(key string, val interface{}) {
+		attrs = append(attrs, slog.Any(key, val))
+	})
+	return attrs
+}
+
+// normalizeLevel maps any spelling of a level to its canonical value, defaulting an unset level to info rather than
+// silently disabling logging
+func normalizeLevel(level iface.Level) (iface.Level, error) {
+	if level == iface.DisabledLevel {
+		return iface.InfoLevel, nil
+	}
+	return iface.LevelFromString(string(level))
+}
+
+func getLogLevel(level iface.Level) slog.Level {
+	switch level {
+	case iface.ErrorLevel:
+		return slog.LevelError
+	case iface.WarnLevel:
+		return slog.LevelWarn
+	case iface.InfoLevel:
+		return slog.LevelInfo
+	case iface.DebugLevel:
+		return slog.LevelDebug
+	case iface.TraceLevel:
+		return LevelTrace
+	}
+	return levelDisabled
+}
+
+// replaceLevelName names the custom trace level "TRACE" instead of slog's default of "DEBUG-4"
+func replaceLevelName(_ []string, a slog.Attr) slog.Attr {
+	if a.Key != slog.LevelKey {
+		return a
+	}
+	if level, ok := a.Value.Any().(slog.Level); ok && level == LevelTrace {
+		a.Value = slog.StringValue("TRACE")
+	}
+	return a
+}
+
+// output is a writer shared by a logger and all loggers nested from it, allowing the destination to be swapped
+type output struct {
+	writer io.Writer
+	lock   sync.RWMutex
+}
+
+func (o *output) Write(p []byte) (int, error) {
+	o.lock.RLock()
+	defer o.lock.RUnlock()
+	return o.writer.Write(p)
+}
+
+func (o *output) set(w io.Writer) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.writer = w
+}
+
+func (o *output) get() io.Writer {
+	o.lock.RLock()
+	defer o.lock.RUnlock()
+	return o.writer
+}
+
+// levelHandler filters records below the configured level before passing them to a caller-provided handler
+type levelHandler struct {
+	level   slog.Level
+	handler slog.Handler
+}
+
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level && h.handler.Enabled(ctx, level)
+}
+
+func (h *levelHandler) Handle(ctx context.Context, r slog.Record) error {
+	return h.handler.Handle(ctx, r)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{level: h.level, handler: h.handler.WithAttrs(attrs)}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{level: h.level, handler: h.handler.WithGroup(name)}
+}
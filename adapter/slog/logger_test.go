@@ -0,0 +1,276 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	iface "github.com/anchore/go-logger"
+)
+
+// capturedRecord is a simplified view of a slog.Record, including any attributes attached via WithAttrs
+type capturedRecord struct {
+	level slog.Level
+	msg   string
+	attrs map[string]interface{}
+}
+
+// captureHandler is a slog.Handler that records everything it handles
+type captureHandler struct {
+	level   slog.Level
+	attrs   []slog.Attr
+	records *[]capturedRecord
+	lock    *sync.Mutex
+}
+
+func newCaptureHandler(level slog.Level) *captureHandler {
+	return &captureHandler{
+		level:   level,
+		records: &[]capturedRecord{},
+		lock:    &sync.Mutex{},
+	}
+}
+
+func (h *captureHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *captureHandler) Handle(_ context.Context, r slog.Record) error {
+	attrs := make(map[string]interface{})
+	for _, a := range h.attrs {
+		attrs[a.Key] = a.Value.Any()
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		attrs[a.Key] = a.Value.Any()
+		return true
+	})
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	*h.records = append(*h.records, capturedRecord{level: r.Level, msg: r.Message, attrs: attrs})
+	return nil
+}
+
+func (h *captureHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *h
+	c.attrs = append(append([]slog.Attr{}, h.attrs...), attrs...)
+	return &c
+}
+
+func (h *captureHandler) WithGroup(_ string) slog.Handler {
+	return h
+}
+
+func (h *captureHandler) captured() []capturedRecord {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return append([]capturedRecord{}, *h.records...)
+}
+
+func TestNewFromSlogHandler(t *testing.T) {
+	h := newCaptureHandler(LevelTrace)
+	l := NewFromSlogHandler(h)
+
+	l.Tracef("trace %d", 1)
+	l.Debug("debug ", 2)
+	l.Infof("info %d", 3)
+	l.Warn("warn")
+	l.Errorf("error %s", "4")
+
+	nested := l.Nested("pkg", "a", iface.Fields{"count": 5})
+	nested.WithFields("file", "b").Info("nested")
+	nested.Nested("child", true).Debug("deeper")
+
+	want := []capturedRecord{
+		{level: LevelTrace, msg: "trace 1", attrs: map[string]interface{}{}},
+		{level: slog.LevelDebug, msg: "debug 2", attrs: map[string]interface{}{}},
+		{level: slog.LevelInfo, msg: "info 3", attrs: map[string]interface{}{}},
+		{level: slog.LevelWarn, msg: "warn", attrs: map[string]interface{}{}},
+		{level: slog.LevelError, msg: "error 4", attrs: map[string]interface{}{}},
+		{level: slog.LevelInfo, msg: "nested", attrs: map[string]interface{}{"pkg": "a", "count": int64(5), "file": "b"}},
+		{level: slog.LevelDebug, msg: "deeper", attrs: map[string]interface{}{"pkg": "a", "count": int64(5), "child": true}},
+	}
+	assert.Equal(t, want, h.captured())
+}
+
+func TestNewFromSlogHandler_RespectsHandlerLevel(t *testing.T) {
+	h := newCaptureHandler(slog.LevelInfo)
+	l := NewFromSlogHandler(h)
+
+	l.Trace("dropped")
+	l.Debug("dropped")
+	l.Info("kept")
+
+	records := h.captured()
+	require.Len(t, records, 1)
+	assert.Equal(t, "kept", records[0].msg)
+}
+
+func TestNew_Levels(t *testing.T) {
+	tests := []struct {
+		name    string
+		level   iface.Level
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "trace",
+			level:   iface.TraceLevel,
+			want:    []string{"level=TRACE msg=t", "level=DEBUG msg=d", "level=INFO msg=i", "level=WARN msg=w", "level=ERROR msg=e"},
+			notWant: []string{"DEBUG-4"},
+		},
+		{
+			name:    "debug",
+			level:   iface.DebugLevel,
+			want:    []string{"level=DEBUG msg=d", "level=INFO msg=i", "level=WARN msg=w", "level=ERROR msg=e"},
+			notWant: []string{"msg=t"},
+		},
+		{
+			name:    "warn",
+			level:   iface.WarnLevel,
+			want:    []string{"level=WARN msg=w", "level=ERROR msg=e"},
+			notWant: []string{"msg=t", "msg=d", "msg=i"},
+		},
+		{
+			name:    "unset defaults to info",
+			level:   iface.DisabledLevel,
+			want:    []string{"level=INFO msg=i", "level=WARN msg=w", "level=ERROR msg=e"},
+			notWant: []string{"msg=t", "msg=d"},
+		},
+		{
+			name:    "uppercase level",
+			level:   "WARN",
+			want:    []string{"level=WARN msg=w", "level=ERROR msg=e"},
+			notWant: []string{"msg=t", "msg=d", "msg=i"},
+		},
+		{
+			name:    "alias",
+			level:   "debugging",
+			want:    []string{"level=DEBUG msg=d", "level=INFO msg=i", "level=WARN msg=w", "level=ERROR msg=e"},
+			notWant: []string{"msg=t"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := New(Config{Level: tt.level})
+			require.NoError(t, err)
+
+			buff := &bytes.Buffer{}
+			l.(iface.Controller).SetOutput(buff)
+
+			l.Trace("t")
+			l.Debug("d")
+			l.Info("i")
+			l.Warn("w")
+			l.Error("e")
+
+			result := buff.String()
+			for _, w := range tt.want {
+				assert.Contains(t, result, w)
+			}
+			for _, w := range tt.notWant {
+				assert.NotContains(t, result, w)
+			}
+		})
+	}
+}
+
+func TestNew_UnknownLevel(t *testing.T) {
+	_, err := New(Config{Level: "verbose-ish"})
+
+	var levelErr *iface.UnknownLevelError
+	require.ErrorAs(t, err, &levelErr)
+	assert.Equal(t, "verbose-ish", levelErr.Value)
+}
+
+func TestNew_NestedAccumulatesAttributes(t *testing.T) {
+	l, err := New(Config{Level: iface.InfoLevel, JSON: true})
+	require.NoError(t, err)
+
+	nested := l.Nested("pkg", "a").Nested(iface.Fields{"count": 3})
+
+	// output set on the root logger applies to already nested loggers
+	buff := &bytes.Buffer{}
+	l.(iface.Controller).SetOutput(buff)
+	assert.Same(t, buff, nested.(iface.Controller).GetOutput())
+
+	nested.WithFields("file", "b").Info("hello")
+
+	var record map[string]interface{}
+	require.NoError(t, json.Unmarshal(buff.Bytes(), &record))
+	assert.Equal(t, "hello", record["msg"])
+	assert.Equal(t, "a", record["pkg"])
+	assert.Equal(t, float64(3), record["count"])
+	assert.Equal(t, "b", record["file"])
+}
+
+func TestNew_WithHandler(t *testing.T) {
+	buff := &bytes.Buffer{}
+	l, err := New(Config{
+		Level:   iface.WarnLevel,
+		Handler: slog.NewJSONHandler(buff, &slog.HandlerOptions{Level: LevelTrace}),
+	})
+	require.NoError(t, err)
+
+	l.Info("filtered by the configured level")
+	l.Nested("pkg", "a").Warn("kept")
+
+	lines := strings.Split(strings.TrimSpace(buff.String()), "\n")
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], `"msg":"kept"`)
+	assert.Contains(t, lines[0], `"pkg":"a"`)
+
+	// the handler owns the output
+	assert.Nil(t, l.(iface.Controller).GetOutput())
+}
+
+func TestNew_FileLocation(t *testing.T) {
+	tests := []struct {
+		name          string
+		truncateFiles bool
+		wantExisting  bool
+	}{
+		{name: "appends by default", wantExisting: true},
+		{name: "truncate", truncateFiles: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logFile := filepath.Join(t.TempDir(), "app.log")
+			require.NoError(t, os.WriteFile(logFile, []byte("existing line\n"), 0600))
+
+			l, err := New(Config{
+				FileLocation:  logFile,
+				Level:         iface.InfoLevel,
+				TruncateFiles: tt.truncateFiles,
+			})
+			require.NoError(t, err)
+			l.Info("new line")
+
+			contents, err := os.ReadFile(logFile)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantExisting, strings.Contains(string(contents), "existing line"))
+			assert.Contains(t, string(contents), "new line")
+		})
+	}
+}
+
+func TestNew_IsLevelEnabled(t *testing.T) {
+	l, err := New(Config{Level: iface.InfoLevel})
+	require.NoError(t, err)
+
+	e, ok := l.(iface.LevelEnabler)
+	require.True(t, ok)
+	assert.True(t, e.IsLevelEnabled(iface.ErrorLevel))
+	assert.True(t, e.IsLevelEnabled(iface.InfoLevel))
+	assert.False(t, e.IsLevelEnabled(iface.DebugLevel))
+	assert.False(t, e.IsLevelEnabled("bogus"))
+}
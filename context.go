@@ -0,0 +1,34 @@
+package logger
+
+import (
+	"context"
+)
+
+// ContextLogger is implemented by loggers that can attach fields carried by a context.Context (e.g. a request ID or
+// trace ID) to all entries.
+type ContextLogger interface {
+	// WithContext returns a logger nested with all fields extracted from the given context by the extractors the
+	// logger was configured with.
+	WithContext(ctx context.Context) Logger
+}
+
+// ContextExtractor returns a single field from the given context, or false when the context does not carry it.
+type ContextExtractor func(ctx context.Context) (key string, val interface{}, ok bool)
+
+// ContextFields returns all fields found in the given context by the extractors as key-value pairs (as accepted by
+// Nested and WithFields). This is intended for implementing ContextLogger.WithContext.
+func ContextFields(ctx context.Context, extractors ...ContextExtractor) []interface{} {
+	if ctx == nil {
+		return nil
+	}
+	var fields []interface{}
+	for _, extract := range extractors {
+		if extract == nil {
+			continue
+		}
+		if key, val, ok := extract(ctx); ok {
+			fields = append(fields, key, val)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type contextKey string
+
+func TestContextFields(t *testing.T) {
+	requestID := func(ctx context.Context) (string, interface{}, bool) {
+		v, ok := ctx.Value(contextKey("request-id")).(string)
+		return "request-id", v, ok
+	}
+	traceID := func(ctx context.Context) (string, interface{}, bool) {
+		v, ok := ctx.Value(contextKey("trace-id")).(string)
+		return "trace-id", v, ok
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want []interface{}
+	}{
+		{
+			name: "all fields present",
+			ctx:  context.WithValue(context.WithValue(context.Background(), contextKey("request-id"), "req-1"), contextKey("trace-id"), "trace-1"),
+			want: []interface{}{"request-id", "req-1", "trace-id", "trace-1"},
+		},
+		{
+			name: "some fields present",
+			ctx:  context.WithValue(context.Background(), contextKey("trace-id"), "trace-1"),
+			want: []interface{}{"trace-id", "trace-1"},
+		},
+		{
+			name: "no fields present",
+			ctx:  context.Background(),
+		},
+		{
+			name: "nil context",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ContextFields(tt.ctx, requestID, nil, traceID))
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package logger
+
+import (
+	"context"
+	"time"
+)
+
+// nearDeadlineField is the field set on entries logged when the deadline of the logger's context is close
+const nearDeadlineField = "near_deadline"
+
+var _ Logger = (*deadlineAwareLogger)(nil)
+var _ ContextLogger = (*deadlineAwareLogger)(nil)
+
+// deadlineAwareLogger tags entries with nearDeadlineField when the deadline of its context is within the threshold
+type deadlineAwareLogger struct {
+	log       MessageLogger
+	ctx       context.Context
+	threshold time.Duration
+}
+
+// WithDeadlineAwareness wraps the given logger such that loggers returned by WithContext tag every entry with
+// "near_deadline=true" while the deadline of the context is within the given threshold (or has passed), helping to
+// diagnose slow requests. The context is also passed to the given logger when it is a ContextLogger. Entries are not
+// tagged when no context has been attached, or the context has no deadline. When threshold is not positive the given
+// logger is returned as-is.
+func WithDeadlineAwareness(l Logger, threshold time.Duration) Logger {
+	if threshold <= 0 {
+		return l
+	}
+	return &deadlineAwareLogger{
+		log:       l,
+		threshold: threshold,
+	}
+}
+
+// WithContext returns a logger that tags entries while the deadline of the given context is near.
+func (d *deadlineAwareLogger) WithContext(ctx context.Context) Logger {
+	l := d.log
+	if cl, ok := d.log.(ContextLogger); ok {
+		l = cl.WithContext(ctx)
+	}
+	return &deadlineAwareLogger{log: l, ctx: ctx, threshold: d.threshold}
+}
+
+// target returns the logger to emit the next entry to, tagging it when the deadline is near
+func (d *deadlineAwareLogger) target() MessageLogger {
+	if d.ctx == nil {
+		return d.log
+	}
+	deadline, ok := d.ctx.Deadline()
+	if !ok || deadline.Sub(now()) > d.threshold {
+		return d.log
+	}
+	if l, ok := d.log.(FieldLogger); ok {
+		return l.WithFields(nearDeadlineField, true)
+	}
+	return d.log
+}
+
+func (d *deadlineAwareLogger) Errorf(format string, args ...interface{}) {
+	d.target().Errorf(format, args...)
+}
+
+func (d *deadlineAwareLogger) Error(args ...interface{}) {
+	d.target().Error(args...)
+}
+
+func (d *deadlineAwareLogger) Warnf(format string, args ...interface{}) {
+	d.target().Warnf(format, args...)
+}
+
+func (d *deadlineAwareLogger) Warn(args ...interface{}) {
+	d.target().Warn(args...)
+}
+
+func (d *deadlineAwareLogger) Infof(format string, args ...interface{}) {
+	d.target().Infof(format, args...)
+}
+
+func (d *deadlineAwareLogger) Info(args ...interface{}) {
+	d.target().Info(args...)
+}
+
+func (d *deadlineAwareLogger) Debugf(format string, args ...interface{}) {
+	d.target().Debugf(format, args...)
+}
+
+func (d *deadlineAwareLogger) Debug(args ...interface{}) {
+	d.target().Debug(args...)
+}
+
+func (d *deadlineAwareLogger) Tracef(format string, args ...interface{}) {
+	d.target().Tracef(format, args...)
+}
+
+func (d *deadlineAwareLogger) Trace(args ...interface{}) {
+	d.target().Trace(args...)
+}
+
+func (d *deadlineAwareLogger) Logf(level Level, format string, args ...interface{}) {
+	LogfAtLevel(d, level, format, args...)
+}
+
+func (d *deadlineAwareLogger) Log(level Level, args ...interface{}) {
+	LogAtLevel(d, level, args...)
+}
+
+func (d *deadlineAwareLogger) WithFields(fields ...interface{}) MessageLogger {
+	if l, ok := d.log.(FieldLogger); ok {
+		return &deadlineAwareLogger{log: l.WithFields(fields...), ctx: d.ctx, threshold: d.threshold}
+	}
+	return d
+}
+
+func (d *deadlineAwareLogger) Nested(fields ...interface{}) Logger {
+	if l, ok := d.log.(NestedLogger); ok {
+		return &deadlineAwareLogger{log: l.Nested(fields...), ctx: d.ctx, threshold: d.threshold}
+	}
+	return d
+}
@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithDeadlineAwareness(t *testing.T) {
+	current := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	original := now
+	now = func() time.Time { return current }
+	t.Cleanup(func() { now = original })
+
+	ctx, cancel := context.WithDeadline(context.Background(), current.Add(10*time.Second))
+	t.Cleanup(cancel)
+
+	rec := newRecordingLogger()
+	l := WithDeadlineAwareness(rec, 2*time.Second)
+	scoped := l.(ContextLogger).WithContext(ctx).Nested("request", "r-1")
+	unscoped := l.(ContextLogger).WithContext(context.Background())
+
+	scoped.Info("started")
+	l.Info("no context")
+
+	current = current.Add(9 * time.Second)
+	scoped.Info("nearly out of time")
+	scoped.WithFields("step", 2).Warn("still going")
+	unscoped.Info("no deadline")
+	l.Info("no context")
+
+	current = current.Add(5 * time.Second)
+	scoped.Log(ErrorLevel, "deadline passed")
+
+	assert.Equal(t, []recordedMessage{
+		{level: InfoLevel, msg: "started", fields: Fields{"request": "r-1"}},
+		{level: InfoLevel, msg: "no context"},
+		{level: InfoLevel, msg: "nearly out of time", fields: Fields{"request": "r-1", "near_deadline": true}},
+		{level: WarnLevel, msg: "still going", fields: Fields{"request": "r-1", "step": 2, "near_deadline": true}},
+		{level: InfoLevel, msg: "no deadline"},
+		{level: InfoLevel, msg: "no context"},
+		{level: ErrorLevel, msg: "deadline passed", fields: Fields{"request": "r-1", "near_deadline": true}},
+	}, rec.recorded())
+}
+
+func TestWithDeadlineAwareness_Disabled(t *testing.T) {
+	rec := newRecordingLogger()
+	require.Same(t, rec, WithDeadlineAwareness(rec, 0))
+}
@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+)
+
+// RangeFields calls fn for every key-value field within the given fields, in the form accepted by Nested and
+// WithFields: alternating keys and values, with Fields maps allowed anywhere among them (whose entries are visited in
+// key order). Keys are formatted with %s, and a trailing key without a value is ignored. This is intended for
+// implementing Nested and WithFields in adapters.
+func RangeFields(fields []interface{}, fn func(key string, val interface{})) {
+	offset := 0
+	for i, val := range fields {
+		// there can be a fields map anywhere within the parameters
+		if fieldsMap, ok := val.(Fields); ok {
+			keys := make([]string, 0, len(fieldsMap))
+			for k := range fieldsMap {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				fn(k, fieldsMap[k])
+			}
+			offset++
+			continue
+		}
+
+		// virtually skip any field maps found when figuring if this is a key or a value
+		if (i-offset)%2 != 0 {
+			fn(fmt.Sprintf("%s", fields[i-1]), val)
+		}
+	}
+}
+
+// FieldsFrom returns all key-value fields within the given fields (see RangeFields) as a map, where later values
+// replace earlier values for the same key.
+func FieldsFrom(fields ...interface{}) Fields {
+	f := make(Fields)
+	RangeFields(fields, func(key string, val interface{}) {
+		f[key] = val
+	})
+	return f
+}
@@ -0,0 +1,39 @@
+package logger
+
+// errorField is the field name errors are attached under by FieldsBuilder.Err
+const errorField = "error"
+
+// FieldsBuilder assembles key-value pairs for WithFields and Nested, ensuring every key is a string paired with a
+// value. The zero value is ready to use, e.g.:
+//
+//	log.WithFields(new(FieldsBuilder).Str("image", ref).Int("layers", n).Err(err).Build()...).Info("cataloged")
+type FieldsBuilder struct {
+	fields []interface{}
+}
+
+// Str adds a string field.
+func (b *FieldsBuilder) Str(key, val string) *FieldsBuilder {
+	b.fields = append(b.fields, key, val)
+	return b
+}
+
+// Int adds an integer field.
+func (b *FieldsBuilder) Int(key string, val int) *FieldsBuilder {
+	b.fields = append(b.fields, key, val)
+	return b
+}
+
+// Err adds the given error as the "error" field, adding nothing when the error is nil.
+func (b *FieldsBuilder) Err(err error) *FieldsBuilder {
+	if err == nil {
+		return b
+	}
+	b.fields = append(b.fields, errorField, err)
+	return b
+}
+
+// Build returns the key-value pairs added so far. The builder may continue to be used afterwards without affecting the
+// returned pairs.
+func (b *FieldsBuilder) Build() []interface{} {
+	return append([]interface{}(nil), b.fields...)
+}
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldsBuilder(t *testing.T) {
+	err := errors.New("boom")
+
+	tests := []struct {
+		name  string
+		build func(b *FieldsBuilder) *FieldsBuilder
+		want  []interface{}
+	}{
+		{
+			name:  "empty",
+			build: func(b *FieldsBuilder) *FieldsBuilder { return b },
+		},
+		{
+			name: "typed fields in order",
+			build: func(b *FieldsBuilder) *FieldsBuilder {
+				return b.Str("image", "alpine:3").Int("layers", 2).Err(err)
+			},
+			want: []interface{}{"image", "alpine:3", "layers", 2, "error", err},
+		},
+		{
+			name: "nil errors are omitted",
+			build: func(b *FieldsBuilder) *FieldsBuilder {
+				return b.Str("image", "alpine:3").Err(nil)
+			},
+			want: []interface{}{"image", "alpine:3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.build(&FieldsBuilder{}).Build())
+		})
+	}
+}
+
+func TestFieldsBuilder_WithFields(t *testing.T) {
+	rec := newRecordingLogger()
+	b := new(FieldsBuilder).Str("image", "alpine:3").Int("layers", 2)
+
+	fields := b.Build()
+	b.Str("ignored", "value")
+	rec.WithFields(fields...).Info("cataloged")
+
+	assert.Equal(t, []recordedMessage{
+		{level: InfoLevel, msg: "cataloged", fields: Fields{"image": "alpine:3", "layers": 2}},
+	}, rec.recorded())
+}
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldsFrom(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []interface{}
+		want   Fields
+	}{
+		{
+			name: "no fields",
+			want: Fields{},
+		},
+		{
+			name:   "key-value pairs",
+			fields: []interface{}{"a", 1, "b", "two"},
+			want:   Fields{"a": 1, "b": "two"},
+		},
+		{
+			name:   "fields map among pairs",
+			fields: []interface{}{"a", 1, Fields{"b": 2, "c": 3}, "d", 4},
+			want:   Fields{"a": 1, "b": 2, "c": 3, "d": 4},
+		},
+		{
+			name:   "trailing key without a value",
+			fields: []interface{}{"a", 1, "b"},
+			want:   Fields{"a": 1},
+		},
+		{
+			name:   "later values win",
+			fields: []interface{}{"a", 1, "a", 2},
+			want:   Fields{"a": 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FieldsFrom(tt.fields...))
+		})
+	}
+}
+
+func TestRangeFields(t *testing.T) {
+	var keys []string
+	RangeFields([]interface{}{"z", 1, Fields{"b": 2, "a": 3}, "c", 4}, func(key string, _ interface{}) {
+		keys = append(keys, key)
+	})
+
+	// pairs are visited in order, with the entries of maps visited in key order
+	assert.Equal(t, []string{"z", "a", "b", "c"}, keys)
+}
@@ -0,0 +1,24 @@
+// Package logfile opens log files the same way for every adapter that writes to files.
+package logfile
+
+import (
+	"io/fs"
+	"os"
+)
+
+// DefaultPermissions are used when creating log files when no permissions are configured
+const DefaultPermissions fs.FileMode = 0644
+
+// Open opens the log file at the given location for writing, creating it with the given permissions (or
+// DefaultPermissions when zero) if it does not exist. Entries are appended to any existing content, unless truncate is
+// set in which case existing content is discarded.
+func Open(location string, truncate bool, perm fs.FileMode) (*os.File, error) {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	if truncate {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	if perm == 0 {
+		perm = DefaultPermissions
+	}
+	return os.OpenFile(location, flags, perm)
+}
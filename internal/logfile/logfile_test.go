@@ -0,0 +1,37 @@
+package logfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpen(t *testing.T) {
+	tests := []struct {
+		name     string
+		truncate bool
+		want     string
+	}{
+		{name: "appends by default", want: "existing\nnew\n"},
+		{name: "truncate", truncate: true, want: "new\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			location := filepath.Join(t.TempDir(), "app.log")
+			require.NoError(t, os.WriteFile(location, []byte("existing\n"), 0600))
+
+			f, err := Open(location, tt.truncate, 0)
+			require.NoError(t, err)
+			_, err = f.WriteString("new\n")
+			require.NoError(t, err)
+			require.NoError(t, f.Close())
+
+			contents, err := os.ReadFile(location)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(contents))
+		})
+	}
+}
@@ -27,6 +27,23 @@ func Levels() []Level {
 	}
 }
 
+// Syslog returns the closest syslog severity keyword for the level (as used by syslog and journald). Syslog has no
+// severity more verbose than debug, so trace is reported as debug. An empty string is returned for DisabledLevel and
+// unknown levels.
+func (l Level) Syslog() string {
+	switch l {
+	case ErrorLevel:
+		return "err"
+	case WarnLevel:
+		return "warning"
+	case InfoLevel:
+		return "info"
+	case DebugLevel, TraceLevel:
+		return "debug"
+	}
+	return ""
+}
+
 type Logger interface {
 	MessageLogger
 	FieldLogger
@@ -38,6 +55,14 @@ type Controller interface {
 	GetOutput() io.Writer
 }
 
+// Cloner is implemented by loggers that can be copied with an independent output (e.g. to capture a subset of logs in
+// a test), where the clone also implements Controller.
+type Cloner interface {
+	// Clone returns a copy of the logger with the same level, formatting and fields. Calling SetOutput on the clone
+	// never affects the logger it was cloned from.
+	Clone() Logger
+}
+
 type NestedLogger interface {
 	Nested(fields ...interface{}) Logger
 }
@@ -54,12 +79,21 @@ type MessageLogger interface {
 	InfoMessageLogger
 	DebugMessageLogger
 	TraceMessageLogger
+	LevelMessageLogger
+}
+
+// LevelMessageLogger logs at a level that is only known at runtime. Unknown levels (including DisabledLevel) are not
+// logged.
+type LevelMessageLogger interface {
+	Logf(level Level, format string, args ...interface{})
+	Log(level Level, args ...interface{})
 }
 
-// type MessageLogger interface {
-//	Logf(level Level, format string, args ...interface{})
-//	Log(level Level, args ...interface{})
-//}
+// LevelEnabler may be implemented by loggers that can report whether messages at a level would be logged at all, so
+// that wrappers can avoid work (or accounting) for messages that would be discarded.
+type LevelEnabler interface {
+	IsLevelEnabled(level Level) bool
+}
 
 type ErrorMessageLogger interface {
 	Errorf(format string, args ...interface{})
@@ -86,6 +120,40 @@ type TraceMessageLogger interface {
 	Trace(args ...interface{})
 }
 
+// LogAtLevel logs the given arguments with the method of the logger for the given level, doing nothing for unknown
+// levels (including DisabledLevel). This is intended for implementing LevelMessageLogger.Log.
+func LogAtLevel(l MessageLogger, level Level, args ...interface{}) {
+	switch level {
+	case ErrorLevel:
+		l.Error(args...)
+	case WarnLevel:
+		l.Warn(args...)
+	case InfoLevel:
+		l.Info(args...)
+	case DebugLevel:
+		l.Debug(args...)
+	case TraceLevel:
+		l.Trace(args...)
+	}
+}
+
+// LogfAtLevel logs the formatted message with the method of the logger for the given level, doing nothing for
+// unknown levels (including DisabledLevel). This is intended for implementing LevelMessageLogger.Logf.
+func LogfAtLevel(l MessageLogger, level Level, format string, args ...interface{}) {
+	switch level {
+	case ErrorLevel:
+		l.Errorf(format, args...)
+	case WarnLevel:
+		l.Warnf(format, args...)
+	case InfoLevel:
+		l.Infof(format, args...)
+	case DebugLevel:
+		l.Debugf(format, args...)
+	case TraceLevel:
+		l.Tracef(format, args...)
+	}
+}
+
 func LevelFromString(l string) (Level, error) {
 	switch strings.ToLower(l) {
 	case "":
@@ -102,7 +170,50 @@ func LevelFromString(l string) (Level, error) {
 		return TraceLevel, nil
 	}
 
-	return Level(l), fmt.Errorf("not a valid log level: %q", l)
+	return Level(l), &UnknownLevelError{Value: l}
+}
+
+// UnknownLevelError is returned when parsing a string that does not name a level
+type UnknownLevelError struct {
+	Value string
+}
+
+func (e *UnknownLevelError) Error() string {
+	return fmt.Sprintf("not a valid log level: %q", e.Value)
+}
+
+// ParseLevel parses a level name case-insensitively, ignoring surrounding whitespace, and accepting the same aliases
+// as LevelFromString (e.g. "warning" for WarnLevel). An empty string is parsed as DisabledLevel, and any unknown value
+// results in an *UnknownLevelError.
+func ParseLevel(s string) (Level, error) {
+	level, err := LevelFromString(strings.TrimSpace(s))
+	if err != nil {
+		return DisabledLevel, err
+	}
+	return level, nil
+}
+
+// Valid reports whether the level is one of the known levels (including DisabledLevel).
+func (l Level) Valid() bool {
+	return l == DisabledLevel || IsLevel(l, Levels()...)
+}
+
+// MarshalText implements encoding.TextMarshaler, returning an error for unknown levels.
+func (l Level) MarshalText() ([]byte, error) {
+	if !l.Valid() {
+		return nil, &UnknownLevelError{Value: string(l)}
+	}
+	return []byte(l), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, parsing the level as with ParseLevel.
+func (l *Level) UnmarshalText(text []byte) error {
+	level, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*l = level
+	return nil
 }
 
 func LevelFromVerbosity(v int, levels ...Level) Level {
@@ -118,6 +229,13 @@ func LevelFromVerbosity(v int, levels ...Level) Level {
 	return levels[v]
 }
 
+// DefaultLevelForVerbosity returns the level for the given verbosity (e.g. the number of -v flags given on the command
+// line) using the canonical ordering shared by all tools: warn (no flags), info (-v), debug (-vv) and trace (-vvv or
+// more).
+func DefaultLevelForVerbosity(v int) Level {
+	return LevelFromVerbosity(v, WarnLevel, InfoLevel, DebugLevel, TraceLevel)
+}
+
 func IsLevel(l Level, levels ...Level) bool {
 	for _, level := range levels {
 		if l == level {
@@ -1,9 +1,12 @@
 package logger
 
 import (
+	"encoding/json"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLevelFromVerbosity(t *testing.T) {
@@ -82,3 +85,132 @@ func TestLevelFromVerbosity(t *testing.T) {
 		})
 	}
 }
+
+func TestDefaultLevelForVerbosity(t *testing.T) {
+	tests := []struct {
+		v    int
+		want Level
+	}{
+		{v: -1, want: WarnLevel},
+		{v: 0, want: WarnLevel},
+		{v: 1, want: InfoLevel},
+		{v: 2, want: DebugLevel},
+		{v: 3, want: TraceLevel},
+		{v: 4, want: TraceLevel},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("v=%d", tt.v), func(t *testing.T) {
+			assert.Equal(t, tt.want, DefaultLevelForVerbosity(tt.v))
+		})
+	}
+}
+
+func TestLogAtLevel(t *testing.T) {
+	tests := []struct {
+		level Level
+		want  []recordedMessage
+	}{
+		{level: ErrorLevel, want: []recordedMessage{{level: ErrorLevel, msg: "plain"}, {level: ErrorLevel, msg: "formatted 1"}}},
+		{level: WarnLevel, want: []recordedMessage{{level: WarnLevel, msg: "plain"}, {level: WarnLevel, msg: "formatted 1"}}},
+		{level: InfoLevel, want: []recordedMessage{{level: InfoLevel, msg: "plain"}, {level: InfoLevel, msg: "formatted 1"}}},
+		{level: DebugLevel, want: []recordedMessage{{level: DebugLevel, msg: "plain"}, {level: DebugLevel, msg: "formatted 1"}}},
+		{level: TraceLevel, want: []recordedMessage{{level: TraceLevel, msg: "plain"}, {level: TraceLevel, msg: "formatted 1"}}},
+		{level: DisabledLevel, want: []recordedMessage{}},
+		{level: Level("bogus"), want: []recordedMessage{}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%q", tt.level), func(t *testing.T) {
+			rec := newRecordingLogger()
+			LogAtLevel(rec, tt.level, "plain")
+			LogfAtLevel(rec, tt.level, "formatted %d", 1)
+			assert.Equal(t, tt.want, rec.recorded())
+		})
+	}
+}
+
+func TestLevel_Syslog(t *testing.T) {
+	tests := []struct {
+		level Level
+		want  string
+	}{
+		{level: ErrorLevel, want: "err"},
+		{level: WarnLevel, want: "warning"},
+		{level: InfoLevel, want: "info"},
+		{level: DebugLevel, want: "debug"},
+		{level: TraceLevel, want: "debug"},
+		{level: DisabledLevel, want: ""},
+		{level: Level("bogus"), want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%q", tt.level), func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.level.Syslog())
+		})
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    Level
+		wantErr bool
+	}{
+		{input: "error", want: ErrorLevel},
+		{input: "warn", want: WarnLevel},
+		{input: "info", want: InfoLevel},
+		{input: "debug", want: DebugLevel},
+		{input: "trace", want: TraceLevel},
+		{input: "  INFO\n", want: InfoLevel},
+		{input: "Warning", want: WarnLevel},
+		{input: "err", want: ErrorLevel},
+		{input: "", want: DisabledLevel},
+		{input: "verbose", wantErr: true},
+		{input: "in fo", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%q", tt.input), func(t *testing.T) {
+			got, err := ParseLevel(tt.input)
+			if tt.wantErr {
+				var levelErr *UnknownLevelError
+				require.ErrorAs(t, err, &levelErr)
+				assert.Equal(t, tt.input, levelErr.Value)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.True(t, got.Valid())
+		})
+	}
+}
+
+func TestLevel_Valid(t *testing.T) {
+	for _, l := range append(Levels(), DisabledLevel) {
+		assert.True(t, l.Valid(), "level %q", l)
+	}
+	assert.False(t, Level("INFO").Valid())
+	assert.False(t, Level("bogus").Valid())
+}
+
+func TestLevel_TextMarshaling(t *testing.T) {
+	type config struct {
+		Level Level `json:"level"`
+	}
+
+	for _, l := range append(Levels(), DisabledLevel) {
+		data, err := json.Marshal(config{Level: l})
+		require.NoError(t, err)
+
+		var got config
+		require.NoError(t, json.Unmarshal(data, &got))
+		assert.Equal(t, l, got.Level)
+	}
+
+	var got config
+	require.NoError(t, json.Unmarshal([]byte(`{"level": " Warning "}`), &got))
+	assert.Equal(t, WarnLevel, got.Level)
+
+	var levelErr *UnknownLevelError
+	assert.ErrorAs(t, json.Unmarshal([]byte(`{"level": "bogus"}`), &got), &levelErr)
+
+	_, err := json.Marshal(config{Level: "bogus"})
+	assert.ErrorAs(t, err, &levelErr)
+}
@@ -0,0 +1,33 @@
+package logger
+
+import (
+	"time"
+)
+
+// Record is a single log entry as seen by Middleware, before it has been formatted.
+type Record struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	Fields  Fields
+}
+
+// Middleware may modify a record before it is emitted (e.g. to add computed fields or redact content), or return
+// false to drop the record entirely.
+type Middleware func(Record) (Record, bool)
+
+// ApplyMiddleware passes the record through each middleware in order, returning false as soon as any middleware drops
+// the record.
+func ApplyMiddleware(r Record, middleware ...Middleware) (Record, bool) {
+	for _, m := range middleware {
+		if m == nil {
+			continue
+		}
+		var keep bool
+		r, keep = m(r)
+		if !keep {
+			return r, false
+		}
+	}
+	return r, true
+}
@@ -0,0 +1,35 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyMiddleware(t *testing.T) {
+	addField := func(r Record) (Record, bool) {
+		fields := Fields{"computed": len(r.Message)}
+		for k, v := range r.Fields {
+			fields[k] = v
+		}
+		r.Fields = fields
+		return r, true
+	}
+	dropNoisy := func(r Record) (Record, bool) {
+		return r, !strings.Contains(r.Message, "noisy")
+	}
+
+	got, keep := ApplyMiddleware(Record{Level: InfoLevel, Message: "hello"}, addField, nil, dropNoisy)
+	assert.True(t, keep)
+	assert.Equal(t, Record{Level: InfoLevel, Message: "hello", Fields: Fields{"computed": 5}}, got)
+
+	calls := 0
+	counter := func(r Record) (Record, bool) {
+		calls++
+		return r, true
+	}
+	_, keep = ApplyMiddleware(Record{Level: InfoLevel, Message: "noisy"}, dropNoisy, counter)
+	assert.False(t, keep)
+	assert.Zero(t, calls, "middleware after a dropped record should not be called")
+}
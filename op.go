@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"time"
+)
+
+// now is the clock used to time operations, rate limits and deadlines (replaceable for testing)
+var now = time.Now
+
+// OpLogger is a Logger scoped to a single operation, which records when the operation started.
+type OpLogger interface {
+	Logger
+	// Done logs the elapsed time since the operation started along with the operation status. A nil error is logged
+	// at info level with status "ok", otherwise the error is logged at error level with status "failed".
+	Done(err error)
+}
+
+type opLogger struct {
+	Logger
+	name  string
+	start time.Time
+}
+
+// StartOp returns a logger nested with an "op" field for the given operation name, recording the start time of the
+// operation so that Done can report the elapsed duration.
+func StartOp(l Logger, name string) OpLogger {
+	return &opLogger{
+		Logger: l.Nested("op", name),
+		name:   name,
+		start:  now(),
+	}
+}
+
+func (o *opLogger) Done(err error) {
+	elapsed := now().Sub(o.start)
+	if err != nil {
+		o.WithFields("elapsed", elapsed, "status", "failed").Errorf("%s failed: %v", o.name, err)
+		return
+	}
+	o.WithFields("elapsed", elapsed, "status", "ok").Infof("%s completed", o.name)
+}
@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartOp(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want recordedMessage
+	}{
+		{
+			name: "successful operation",
+			want: recordedMessage{
+				level:  InfoLevel,
+				msg:    "fetch-db completed",
+				fields: Fields{"op": "fetch-db", "elapsed": 1500 * time.Millisecond, "status": "ok"},
+			},
+		},
+		{
+			name: "failed operation",
+			err:  errors.New("boom"),
+			want: recordedMessage{
+				level:  ErrorLevel,
+				msg:    "fetch-db failed: boom",
+				fields: Fields{"op": "fetch-db", "elapsed": 1500 * time.Millisecond, "status": "failed"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+			original := now
+			now = func() time.Time { return current }
+			t.Cleanup(func() { now = original })
+
+			rec := newRecordingLogger()
+			op := StartOp(rec, "fetch-db")
+			op.Info("working")
+
+			current = current.Add(1500 * time.Millisecond)
+			op.Done(tt.err)
+
+			messages := rec.recorded()
+			require.Len(t, messages, 2)
+			assert.Equal(t, recordedMessage{level: InfoLevel, msg: "working", fields: Fields{"op": "fetch-db"}}, messages[0])
+			assert.Equal(t, tt.want, messages[1])
+		})
+	}
+}
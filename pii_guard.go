@@ -0,0 +1,122 @@
+package logger
+
+import (
+	"fmt"
+)
+
+var _ Logger = (*piiGuardLogger)(nil)
+
+// piiGuardLogger runs every formatted message and field through a scanner before passing it to the wrapped logger
+type piiGuardLogger struct {
+	log         MessageLogger
+	scan        func(string) bool
+	onViolation func(string)
+}
+
+// WithPIIGuard wraps the given logger such that every formatted message (and every field value) is passed to scan,
+// calling onViolation with the offending content whenever scan reports a hit. The message is still passed to the
+// wrapped logger afterwards, so onViolation may panic (e.g. in tests) to prevent emission. When either scan or
+// onViolation is nil the given logger is returned as-is, so the guard costs nothing when disabled.
+func WithPIIGuard(l Logger, scan func(string) bool, onViolation func(string)) Logger {
+	if scan == nil || onViolation == nil {
+		return l
+	}
+	return &piiGuardLogger{
+		log:         l,
+		scan:        scan,
+		onViolation: onViolation,
+	}
+}
+
+func (p *piiGuardLogger) check(msg string) {
+	if p.scan(msg) {
+		p.onViolation(msg)
+	}
+}
+
+func (p *piiGuardLogger) checkFields(fields []interface{}) {
+	for _, f := range fields {
+		if m, ok := f.(Fields); ok {
+			for k, v := range m {
+				p.check(k)
+				p.check(fmt.Sprintf("%+v", v))
+			}
+			continue
+		}
+		p.check(fmt.Sprintf("%+v", f))
+	}
+}
+
+func (p *piiGuardLogger) Errorf(format string, args ...interface{}) {
+	p.check(fmt.Sprintf(format, args...))
+	p.log.Errorf(format, args...)
+}
+
+func (p *piiGuardLogger) Error(args ...interface{}) {
+	p.check(fmt.Sprint(args...))
+	p.log.Error(args...)
+}
+
+func (p *piiGuardLogger) Warnf(format string, args ...interface{}) {
+	p.check(fmt.Sprintf(format, args...))
+	p.log.Warnf(format, args...)
+}
+
+func (p *piiGuardLogger) Warn(args ...interface{}) {
+	p.check(fmt.Sprint(args...))
+	p.log.Warn(args...)
+}
+
+func (p *piiGuardLogger) Infof(format string, args ...interface{}) {
+	p.check(fmt.Sprintf(format, args...))
+	p.log.Infof(format, args...)
+}
+
+func (p *piiGuardLogger) Info(args ...interface{}) {
+	p.check(fmt.Sprint(args...))
+	p.log.Info(args...)
+}
+
+func (p *piiGuardLogger) Debugf(format string, args ...interface{}) {
+	p.check(fmt.Sprintf(format, args...))
+	p.log.Debugf(format, args...)
+}
+
+func (p *piiGuardLogger) Debug(args ...interface{}) {
+	p.check(fmt.Sprint(args...))
+	p.log.Debug(args...)
+}
+
+func (p *piiGuardLogger) Tracef(format string, args ...interface{}) {
+	p.check(fmt.Sprintf(format, args...))
+	p.log.Tracef(format, args...)
+}
+
+func (p *piiGuardLogger) Trace(args ...interface{}) {
+	p.check(fmt.Sprint(args...))
+	p.log.Trace(args...)
+}
+
+func (p *piiGuardLogger) Logf(level Level, format string, args ...interface{}) {
+	LogfAtLevel(p, level, format, args...)
+}
+
+func (p *piiGuardLogger) Log(level Level, args ...interface{}) {
+	LogAtLevel(p, level, args...)
+}
+
+func (p *piiGuardLogger) WithFields(fields ...interface{}) MessageLogger {
+	p.checkFields(fields)
+	if l, ok := p.log.(FieldLogger); ok {
+		return &piiGuardLogger{log: l.WithFields(fields...), scan: p.scan, onViolation: p.onViolation}
+	}
+	return p
+}
+
+func (p *piiGuardLogger) Nested(fields ...interface{}) Logger {
+	p.checkFields(fields)
+	if l, ok := p.log.(NestedLogger); ok {
+		return &piiGuardLogger{log: l.Nested(fields...), scan: p.scan, onViolation: p.onViolation}
+	}
+	return p
+}
@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithPIIGuard(t *testing.T) {
+	ssn := regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)
+
+	rec := newRecordingLogger()
+	var violations []string
+	l := WithPIIGuard(rec, ssn.MatchString, func(s string) {
+		violations = append(violations, s)
+	})
+
+	l.Info("user signed in")
+	l.Infof("user ssn is %s", "123-45-6789")
+	l.Warn("order 1234-56-789 shipped")
+	l.Nested("ssn", "987-65-4321").Debug("nested clean message")
+	l.WithFields(Fields{"note": "ok"}).Error("clean")
+
+	assert.Equal(t, []string{"user ssn is 123-45-6789", "987-65-4321"}, violations)
+	// the guard observes but does not prevent emission
+	assert.Len(t, rec.recorded(), 5)
+}
+
+func TestWithPIIGuard_Disabled(t *testing.T) {
+	rec := newRecordingLogger()
+	assert.Same(t, rec, WithPIIGuard(rec, nil, func(string) {}))
+	assert.Same(t, rec, WithPIIGuard(rec, func(string) bool { return true }, nil))
+}
+
+func TestWithPIIGuard_PanicOnViolation(t *testing.T) {
+	rec := newRecordingLogger()
+	l := WithPIIGuard(rec, func(s string) bool { return s == "secret" }, func(s string) {
+		panic("pii logged: " + s)
+	})
+
+	assert.PanicsWithValue(t, "pii logged: secret", func() {
+		l.Error("secret")
+	})
+	assert.Empty(t, rec.recorded())
+}
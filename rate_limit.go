@@ -0,0 +1,170 @@
+package logger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+var _ Logger = (*byteRateLimitLogger)(nil)
+var _ SuppressionSummarizer = (*byteRateLimitLogger)(nil)
+
+// byteRateLimitLogger drops messages once the bytes logged within the current interval exceed the budget
+type byteRateLimitLogger struct {
+	log    MessageLogger
+	budget *byteBudget
+}
+
+// byteBudget tracks the bytes logged within the current interval, and is shared by a logger and all loggers derived
+// from it (with WithFields and Nested)
+type byteBudget struct {
+	SuppressionCounter
+	// log is the unwrapped logger that summaries of dropped bytes are logged to
+	log              MessageLogger
+	bytesPerInterval int
+	interval         time.Duration
+	lock             sync.Mutex
+	windowStart      time.Time
+	used             int
+	droppedBytes     int
+}
+
+// WithByteRateLimit wraps the given logger such that messages are dropped once the total size of formatted messages
+// logged within an interval exceeds bytesPerInterval (fields are not counted). Intervals are fixed windows starting
+// with the first message after the previous interval has elapsed, at which point a warning with the number of bytes
+// dropped during the previous interval is logged (dropped bytes in the final interval can be reported with
+// LogSuppressionSummary, as the returned logger implements SuppressionSummarizer). A single message larger than
+// bytesPerInterval is always dropped. Messages at levels the given logger does not log are only kept from using up the
+// budget when the logger implements LevelEnabler. When either bytesPerInterval or interval is not positive the given logger is
+// returned as-is.
+func WithByteRateLimit(l Logger, bytesPerInterval int, interval time.Duration) Logger {
+	if bytesPerInterval <= 0 || interval <= 0 {
+		return l
+	}
+	return &byteRateLimitLogger{
+		log: l,
+		budget: &byteBudget{
+			log:              l,
+			bytesPerInterval: bytesPerInterval,
+			interval:         interval,
+		},
+	}
+}
+
+// take spends size bytes of the budget for the current interval, reporting whether the message may be logged. The
+// number of bytes dropped during the previous interval is returned when this starts a new interval.
+func (b *byteBudget) take(level Level, size int) (int, bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	var previouslyDropped int
+	if t := now(); b.windowStart.IsZero() || t.Sub(b.windowStart) >= b.interval {
+		previouslyDropped = b.droppedBytes
+		b.windowStart = t
+		b.used = 0
+		b.droppedBytes = 0
+	}
+
+	if b.used+size > b.bytesPerInterval {
+		b.droppedBytes += size
+		b.Suppressed(level)
+		return previouslyDropped, false
+	}
+	b.used += size
+	return previouslyDropped, true
+}
+
+// enabled reports whether the wrapped logger logs messages at the given level (assuming it does when it cannot tell),
+// since messages that would be discarded anyway must not use up the budget
+func (r *byteRateLimitLogger) enabled(level Level) bool {
+	e, ok := r.log.(LevelEnabler)
+	return !ok || e.IsLevelEnabled(level)
+}
+
+func (r *byteRateLimitLogger) emitf(level Level, format string, args ...interface{}) {
+	if r.enabled(level) {
+		r.emit(level, fmt.Sprintf(format, args...))
+	}
+}
+
+func (r *byteRateLimitLogger) emitArgs(level Level, args ...interface{}) {
+	if r.enabled(level) {
+		r.emit(level, fmt.Sprint(args...))
+	}
+}
+
+func (r *byteRateLimitLogger) emit(level Level, msg string) {
+	previouslyDropped, ok := r.budget.take(level, len(msg))
+	if previouslyDropped > 0 {
+		r.budget.log.Warnf("%d bytes of log messages were dropped by the rate limit of %d bytes per %s", previouslyDropped, r.budget.bytesPerInterval, r.budget.interval)
+	}
+	if ok {
+		LogAtLevel(r.log, level, msg)
+	}
+}
+
+func (r *byteRateLimitLogger) Summary() map[Level]int {
+	return r.budget.Summary()
+}
+
+func (r *byteRateLimitLogger) Errorf(format string, args ...interface{}) {
+	r.emitf(ErrorLevel, format, args...)
+}
+
+func (r *byteRateLimitLogger) Error(args ...interface{}) {
+	r.emitArgs(ErrorLevel, args...)
+}
+
+func (r *byteRateLimitLogger) Warnf(format string, args ...interface{}) {
+	r.emitf(WarnLevel, format, args...)
+}
+
+func (r *byteRateLimitLogger) Warn(args ...interface{}) {
+	r.emitArgs(WarnLevel, args...)
+}
+
+func (r *byteRateLimitLogger) Infof(format string, args ...interface{}) {
+	r.emitf(InfoLevel, format, args...)
+}
+
+func (r *byteRateLimitLogger) Info(args ...interface{}) {
+	r.emitArgs(InfoLevel, args...)
+}
+
+func (r *byteRateLimitLogger) Debugf(format string, args ...interface{}) {
+	r.emitf(DebugLevel, format, args...)
+}
+
+func (r *byteRateLimitLogger) Debug(args ...interface{}) {
+	r.emitArgs(DebugLevel, args...)
+}
+
+func (r *byteRateLimitLogger) Tracef(format string, args ...interface{}) {
+	r.emitf(TraceLevel, format, args...)
+}
+
+func (r *byteRateLimitLogger) Trace(args ...interface{}) {
+	r.emitArgs(TraceLevel, args...)
+}
+
+func (r *byteRateLimitLogger) Logf(level Level, format string, args ...interface{}) {
+	LogfAtLevel(r, level, format, args...)
+}
+
+func (r *byteRateLimitLogger) Log(level Level, args ...interface{}) {
+	LogAtLevel(r, level, args...)
+}
+
+func (r *byteRateLimitLogger) WithFields(fields ...interface{}) MessageLogger {
+	if l, ok := r.log.(FieldLogger); ok {
+		return &byteRateLimitLogger{log: l.WithFields(fields...), budget: r.budget}
+	}
+	return r
+}
+
+func (r *byteRateLimitLogger) Nested(fields ...interface{}) Logger {
+	if l, ok := r.log.(NestedLogger); ok {
+		return &byteRateLimitLogger{log: l.Nested(fields...), budget: r.budget}
+	}
+	return r
+}
@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithByteRateLimit(t *testing.T) {
+	current := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	original := now
+	now = func() time.Time { return current }
+	t.Cleanup(func() { now = original })
+
+	rec := newRecordingLogger()
+	l := WithByteRateLimit(rec, 10, time.Second)
+
+	l.Info("12345")                             // 5 bytes
+	l.Nested("pkg", "a").Warnf("%d", 1234)      // 9 bytes
+	l.WithFields("key", "value").Error("12345") // 14 bytes, over budget
+	l.Debug("1")                                // 10 bytes
+	l.Trace("1")                                // 11 bytes, over budget
+
+	current = current.Add(500 * time.Millisecond)
+	l.Info("1") // still within the first interval
+
+	current = current.Add(500 * time.Millisecond)
+	l.Info("0123456789") // a new interval, exactly on budget
+	l.Info("a message larger than the budget")
+
+	assert.Equal(t, []recordedMessage{
+		{level: InfoLevel, msg: "12345"},
+		{level: WarnLevel, msg: "1234", fields: Fields{"pkg": "a"}},
+		{level: DebugLevel, msg: "1"},
+		{level: WarnLevel, msg: "7 bytes of log messages were dropped by the rate limit of 10 bytes per 1s"},
+		{level: InfoLevel, msg: "0123456789"},
+	}, rec.recorded())
+
+	assert.Equal(t, map[Level]int{ErrorLevel: 1, TraceLevel: 1, InfoLevel: 2}, l.(SuppressionSummarizer).Summary())
+}
+
+// infoLogger is a recording logger that only logs at info level and above
+type infoLogger struct {
+	*recordingLogger
+}
+
+func (infoLogger) IsLevelEnabled(level Level) bool {
+	return level == InfoLevel || level == WarnLevel || level == ErrorLevel
+}
+
+func TestWithByteRateLimit_IgnoresDisabledLevels(t *testing.T) {
+	rec := newRecordingLogger()
+	l := WithByteRateLimit(infoLogger{rec}, 10, time.Hour)
+
+	// messages the wrapped logger would discard anyway neither use up the budget nor count as dropped
+	l.Debug("0123456789")
+	l.Tracef("%s", "0123456789")
+	l.Info("0123456789")
+
+	assert.Equal(t, []recordedMessage{{level: InfoLevel, msg: "0123456789"}}, rec.recorded())
+	assert.Empty(t, l.(SuppressionSummarizer).Summary())
+}
+
+func TestWithByteRateLimit_Disabled(t *testing.T) {
+	rec := newRecordingLogger()
+	assert.Same(t, rec, WithByteRateLimit(rec, 0, time.Second))
+	assert.Same(t, rec, WithByteRateLimit(rec, 10, 0))
+}
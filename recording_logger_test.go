@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"fmt"
+)
+
+var _ Logger = (*recordingLogger)(nil)
+
+type recordedMessage struct {
+	level  Level
+	msg    string
+	fields Fields
+}
+
+// recordingLogger is a Logger that captures each message, the level it was logged at, and any attached fields
+type recordingLogger struct {
+	messages *[]recordedMessage
+	fields   Fields
+}
+
+func newRecordingLogger() *recordingLogger {
+	return &recordingLogger{messages: &[]recordedMessage{}}
+}
+
+func (r *recordingLogger) recorded() []recordedMessage {
+	return *r.messages
+}
+
+func (r *recordingLogger) record(level Level, args ...interface{}) {
+	var fields Fields
+	if len(r.fields) > 0 {
+		fields = make(Fields)
+		for k, v := range r.fields {
+			fields[k] = v
+		}
+	}
+	*r.messages = append(*r.messages, recordedMessage{level: level, msg: fmt.Sprint(args...), fields: fields})
+}
+
+func (r *recordingLogger) with(fields ...interface{}) *recordingLogger {
+	merged := make(Fields)
+	for k, v := range r.fields {
+		merged[k] = v
+	}
+	for i := 0; i+1 < len(fields); i += 2 {
+		merged[fmt.Sprintf("%s", fields[i])] = fields[i+1]
+	}
+	return &recordingLogger{messages: r.messages, fields: merged}
+}
+
+func (r *recordingLogger) WithFields(fields ...interface{}) MessageLogger { return r.with(fields...) }
+func (r *recordingLogger) Nested(fields ...interface{}) Logger            { return r.with(fields...) }
+
+func (r *recordingLogger) Errorf(format string, args ...interface{}) {
+	r.record(ErrorLevel, fmt.Sprintf(format, args...))
+}
+func (r *recordingLogger) Error(args ...interface{}) { r.record(ErrorLevel, args...) }
+func (r *recordingLogger) Warnf(format string, args ...interface{}) {
+	r.record(WarnLevel, fmt.Sprintf(format, args...))
+}
+func (r *recordingLogger) Warn(args ...interface{}) { r.record(WarnLevel, args...) }
+func (r *recordingLogger) Infof(format string, args ...interface{}) {
+	r.record(InfoLevel, fmt.Sprintf(format, args...))
+}
+func (r *recordingLogger) Info(args ...interface{}) { r.record(InfoLevel, args...) }
+func (r *recordingLogger) Debugf(format string, args ...interface{}) {
+	r.record(DebugLevel, fmt.Sprintf(format, args...))
+}
+func (r *recordingLogger) Debug(args ...interface{}) { r.record(DebugLevel, args...) }
+func (r *recordingLogger) Tracef(format string, args ...interface{}) {
+	r.record(TraceLevel, fmt.Sprintf(format, args...))
+}
+func (r *recordingLogger) Trace(args ...interface{}) { r.record(TraceLevel, args...) }
+func (r *recordingLogger) Logf(level Level, format string, args ...interface{}) {
+	LogfAtLevel(r, level, format, args...)
+}
+func (r *recordingLogger) Log(level Level, args ...interface{}) { LogAtLevel(r, level, args...) }
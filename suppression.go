@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"sort"
+	"sync"
+)
+
+// SuppressionSummarizer is implemented by loggers that may drop messages (e.g. by rate limiting, deduplicating or
+// sampling), reporting how many messages have been dropped at each level.
+type SuppressionSummarizer interface {
+	Summary() map[Level]int
+}
+
+// SuppressionCounter counts suppressed messages by level. It is safe for concurrent use, and is intended to be
+// embedded by loggers that drop messages so that they implement SuppressionSummarizer.
+type SuppressionCounter struct {
+	counts map[Level]int
+	lock   sync.Mutex
+}
+
+// Suppressed records that a message at the given level was dropped.
+func (c *SuppressionCounter) Suppressed(level Level) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.counts == nil {
+		c.counts = make(map[Level]int)
+	}
+	c.counts[level]++
+}
+
+// Summary returns a copy of the number of dropped messages by level (levels without any dropped messages are omitted).
+func (c *SuppressionCounter) Summary() map[Level]int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	summary := make(map[Level]int, len(c.counts))
+	for level, count := range c.counts {
+		summary[level] = count
+	}
+	return summary
+}
+
+// LogSuppressionSummary logs the suppressed message counts of the given summarizer at warn level (e.g. on shutdown),
+// logging nothing if no messages were suppressed. Note that the given logger should not itself suppress this message.
+func LogSuppressionSummary(l MessageLogger, s SuppressionSummarizer) {
+	summary := s.Summary()
+	if len(summary) == 0 {
+		return
+	}
+	levels := make([]string, 0, len(summary))
+	total := 0
+	for level, count := range summary {
+		levels = append(levels, string(level))
+		total += count
+	}
+	sort.Strings(levels)
+	fields := make([]interface{}, 0, 2*len(levels))
+	for _, level := range levels {
+		fields = append(fields, level, summary[Level(level)])
+	}
+	if fl, ok := l.(FieldLogger); ok {
+		fl.WithFields(fields...).Warnf("%d log messages were suppressed", total)
+		return
+	}
+	l.Warnf("%d log messages were suppressed", total)
+}
@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuppressionCounter(t *testing.T) {
+	var c SuppressionCounter
+	assert.Empty(t, c.Summary())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Suppressed(DebugLevel)
+			c.Suppressed(DebugLevel)
+			c.Suppressed(InfoLevel)
+		}()
+	}
+	wg.Wait()
+
+	summary := c.Summary()
+	assert.Equal(t, map[Level]int{DebugLevel: 20, InfoLevel: 10}, summary)
+
+	// the summary is a copy
+	summary[DebugLevel] = 0
+	assert.Equal(t, 20, c.Summary()[DebugLevel])
+}
+
+func TestLogSuppressionSummary(t *testing.T) {
+	rec := newRecordingLogger()
+
+	var c SuppressionCounter
+	LogSuppressionSummary(rec, &c)
+	assert.Empty(t, rec.recorded())
+
+	c.Suppressed(DebugLevel)
+	c.Suppressed(DebugLevel)
+	c.Suppressed(InfoLevel)
+	LogSuppressionSummary(rec, &c)
+
+	assert.Equal(t, []recordedMessage{
+		{
+			level:  WarnLevel,
+			msg:    "3 log messages were suppressed",
+			fields: Fields{"debug": 2, "info": 1},
+		},
+	}, rec.recorded())
+}
@@ -0,0 +1,37 @@
+package logger
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// TraceIDKey is the field key that trace ids are attached to nested loggers under
+const TraceIDKey = "trace_id"
+
+// TraceIDGenerator creates a new unique trace id
+type TraceIDGenerator func() string
+
+// NestedWithTrace returns a logger nested from the given logger with the given fields and a newly generated trace id
+// (see NewTraceID), along with the trace id itself for propagation (e.g. in response headers). All loggers nested
+// from the returned logger inherit the trace id.
+func NestedWithTrace(l Logger, fields ...interface{}) (Logger, string) {
+	return NestedWithTraceGenerator(l, NewTraceID, fields...)
+}
+
+// NestedWithTraceGenerator is NestedWithTrace using the given trace id generator.
+func NestedWithTraceGenerator(l Logger, generate TraceIDGenerator, fields ...interface{}) (Logger, string) {
+	id := generate()
+	// copy the fields to avoid appending to the caller's backing array
+	fields = append(append([]interface{}{}, fields...), TraceIDKey, id)
+	return l.Nested(fields...), id
+}
+
+// NewTraceID returns a random 128-bit trace id as 32 lowercase hex characters (the same shape as a W3C trace id).
+func NewTraceID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand does not fail on supported platforms
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNestedWithTraceGenerator(t *testing.T) {
+	rec := newRecordingLogger()
+
+	l, id := NestedWithTraceGenerator(rec, func() string { return "trace-1" }, "pkg", "server")
+	assert.Equal(t, "trace-1", id)
+
+	l.Info("parent")
+	child := l.Nested("step", "child")
+	child.Debug("child")
+	child.Nested("step", "grandchild").WithFields("extra", 1).Warn("grandchild")
+
+	messages := rec.recorded()
+	require.Len(t, messages, 3)
+	for _, m := range messages {
+		assert.Equal(t, "trace-1", m.fields[TraceIDKey], "message %q", m.msg)
+		assert.Equal(t, "server", m.fields["pkg"], "message %q", m.msg)
+	}
+
+	rec.Info("outside")
+	assert.NotContains(t, rec.recorded()[3].fields, TraceIDKey)
+}
+
+func TestNestedWithTrace(t *testing.T) {
+	rec := newRecordingLogger()
+
+	_, first := NestedWithTrace(rec)
+	_, second := NestedWithTrace(rec)
+
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{32}$`), first)
+	assert.NotEqual(t, first, second)
+}
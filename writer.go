@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"sync"
+)
+
+var _ io.WriteCloser = (*levelWriter)(nil)
+
+// LevelParser inspects a single line and returns the level hint found at the start of the line (if any) along with
+// the remainder of the line that should be logged.
+type LevelParser func(line string) (Level, string, bool)
+
+// levelWriter is an io.Writer that logs each complete line written to it, optionally at the level hinted at the
+// start of the line.
+type levelWriter struct {
+	log    MessageLogger
+	level  Level
+	parser LevelParser
+	buf    bytes.Buffer
+	lock   sync.Mutex
+}
+
+// NewLevelWriter returns an io.WriteCloser that logs every line written to it at the given default level. When a
+// parser is provided, each line is first run through the parser and logged at the parsed level (falling back to the
+// default level when no hint is found). Close must be called to flush any trailing partial line.
+func NewLevelWriter(log MessageLogger, level Level, parser LevelParser) io.WriteCloser {
+	return &levelWriter{
+		log:    log,
+		level:  level,
+		parser: parser,
+	}
+}
+
+func (w *levelWriter) Write(p []byte) (int, error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.buf.Write(p)
+	for {
+		idx := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if idx < 0 {
+			break
+		}
+		line := string(w.buf.Next(idx + 1))
+		w.logLine(strings.TrimRight(line, "\r\n"))
+	}
+	return len(p), nil
+}
+
+// Close logs any buffered partial line.
+func (w *levelWriter) Close() error {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.buf.Len() > 0 {
+		w.logLine(w.buf.String())
+		w.buf.Reset()
+	}
+	return nil
+}
+
+func (w *levelWriter) logLine(line string) {
+	level := w.level
+	if w.parser != nil {
+		if l, rest, ok := w.parser(line); ok {
+			level, line = l, rest
+		}
+	}
+	LogAtLevel(w.log, level, line)
+}
+
+// DefaultLevelParser recognizes a leading level token of the form "ERROR: ..." or "[warn] ...", using the same level
+// names accepted by LevelFromString. Bare words without a delimiter are not treated as hints.
+func DefaultLevelParser(line string) (Level, string, bool) {
+	trimmed := strings.TrimLeft(line, " \t")
+
+	var token, rest string
+	if strings.HasPrefix(trimmed, "[") {
+		end := strings.IndexByte(trimmed, ']')
+		if end < 0 {
+			return DisabledLevel, line, false
+		}
+		token, rest = trimmed[1:end], trimmed[end+1:]
+	} else {
+		end := strings.IndexByte(trimmed, ':')
+		if end < 0 {
+			return DisabledLevel, line, false
+		}
+		token, rest = trimmed[:end], trimmed[end+1:]
+	}
+
+	if len(token) <= 1 {
+		// single character aliases are too ambiguous to be treated as a level hint
+		return DisabledLevel, line, false
+	}
+
+	level, err := LevelFromString(strings.TrimSpace(token))
+	if err != nil || level == DisabledLevel {
+		return DisabledLevel, line, false
+	}
+
+	return level, strings.TrimLeft(rest, " \t"), true
+}
@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevelWriter(t *testing.T) {
+	input := "ERROR: disk full\n" +
+		"[warn] retrying\n" +
+		"plain line\n" +
+		"debug: details here\n" +
+		"information is not a hint\n" +
+		"  TRACE:  deep\n" +
+		"trailing partial"
+
+	tests := []struct {
+		name   string
+		parser LevelParser
+		want   []recordedMessage
+	}{
+		{
+			name:   "no parser logs everything at the default level",
+			parser: nil,
+			want: []recordedMessage{
+				{level: InfoLevel, msg: "ERROR: disk full"},
+				{level: InfoLevel, msg: "[warn] retrying"},
+				{level: InfoLevel, msg: "plain line"},
+				{level: InfoLevel, msg: "debug: details here"},
+				{level: InfoLevel, msg: "information is not a hint"},
+				{level: InfoLevel, msg: "  TRACE:  deep"},
+				{level: InfoLevel, msg: "trailing partial"},
+			},
+		},
+		{
+			name:   "default parser logs at the hinted level",
+			parser: DefaultLevelParser,
+			want: []recordedMessage{
+				{level: ErrorLevel, msg: "disk full"},
+				{level: WarnLevel, msg: "retrying"},
+				{level: InfoLevel, msg: "plain line"},
+				{level: DebugLevel, msg: "details here"},
+				{level: InfoLevel, msg: "information is not a hint"},
+				{level: TraceLevel, msg: "deep"},
+				{level: InfoLevel, msg: "trailing partial"},
+			},
+		},
+		{
+			name: "custom parser",
+			parser: func(line string) (Level, string, bool) {
+				if line == "plain line" {
+					return ErrorLevel, "custom", true
+				}
+				return DisabledLevel, line, false
+			},
+			want: []recordedMessage{
+				{level: InfoLevel, msg: "ERROR: disk full"},
+				{level: InfoLevel, msg: "[warn] retrying"},
+				{level: ErrorLevel, msg: "custom"},
+				{level: InfoLevel, msg: "debug: details here"},
+				{level: InfoLevel, msg: "information is not a hint"},
+				{level: InfoLevel, msg: "  TRACE:  deep"},
+				{level: InfoLevel, msg: "trailing partial"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := newRecordingLogger()
+			w := NewLevelWriter(rec, InfoLevel, tt.parser)
+
+			// write in small chunks to ensure lines split across writes are reassembled
+			data := []byte(input)
+			for len(data) > 0 {
+				n := 5
+				if n > len(data) {
+					n = len(data)
+				}
+				_, err := w.Write(data[:n])
+				require.NoError(t, err)
+				data = data[n:]
+			}
+			require.NoError(t, w.Close())
+
+			assert.Equal(t, tt.want, rec.recorded())
+		})
+	}
+}